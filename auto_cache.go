@@ -1,6 +1,7 @@
 package cache
 
 import (
+	"context"
 	"sync"
 	"time"
 
@@ -12,9 +13,12 @@ type StorageAutoCache struct {
 	logger  IAutoCacheLogger
 	active  bool
 	entries map[string]*EntryAutoCache
+	clock   Clock
 	lock    sync.RWMutex
 }
 
+var _ IAutoCache = &StorageAutoCache{} // StorageAutoCache implements IAutoCache
+
 // NewStorageAutoCacheObject create new instance of StorageAutoCache
 func NewStorageAutoCacheObject(active bool, logger IAutoCacheLogger) *StorageAutoCache {
 	if logger == nil {
@@ -24,9 +28,17 @@ func NewStorageAutoCacheObject(active bool, logger IAutoCacheLogger) *StorageAut
 		logger:  logger,
 		active:  active,
 		entries: map[string]*EntryAutoCache{},
+		clock:   RealClock,
 	}
 }
 
+// SetClock changes clock driving updates of entries put after the call
+func (storage *StorageAutoCache) SetClock(clock Clock) {
+	storage.lock.Lock()
+	storage.clock = clock
+	storage.lock.Unlock()
+}
+
 // Get returns data by given key
 func (storage *StorageAutoCache) Get(key string) (interface{}, error) {
 	entry, err := storage.getEntry(key)
@@ -37,6 +49,36 @@ func (storage *StorageAutoCache) Get(key string) (interface{}, error) {
 	return entry.GetValue()
 }
 
+// GetCtx returns data by given key, it stops waiting for updater when context is done
+func (storage *StorageAutoCache) GetCtx(ctx context.Context, key string) (interface{}, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	entry, err := storage.getEntry(key)
+	if err != nil {
+		return nil, err
+	}
+
+	type result struct {
+		value interface{}
+		err   error
+	}
+
+	done := make(chan result, 1)
+	go func() {
+		value, err := entry.GetValue()
+		done <- result{value, err}
+	}()
+
+	select {
+	case r := <-done:
+		return r.value, r.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
 func (storage *StorageAutoCache) getEntry(key string) (*EntryAutoCache, error) {
 	var err error
 
@@ -67,6 +109,10 @@ func (storage *StorageAutoCache) Remove(key string) {
 func (storage *StorageAutoCache) Put(updater func() (interface{}, error), key string, ttl time.Duration) error {
 	entry := CreateEntryAutoCache(updater, ttl, key, storage.logger)
 
+	storage.lock.RLock()
+	entry.SetClock(storage.clock)
+	storage.lock.RUnlock()
+
 	if storage.active {
 		if err := entry.Start(); err != nil {
 			return errors.Errorf("Auto cache updater \"%s\" error: %s", key, err)
@@ -82,3 +128,23 @@ func (storage *StorageAutoCache) Put(updater func() (interface{}, error), key st
 
 	return nil
 }
+
+// PutCtx puts data into storage unless context is done
+func (storage *StorageAutoCache) PutCtx(ctx context.Context, updater func() (interface{}, error), key string, ttl time.Duration) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	return storage.Put(updater, key, ttl)
+}
+
+// RemoveCtx removes value by key unless context is done
+func (storage *StorageAutoCache) RemoveCtx(ctx context.Context, key string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	storage.Remove(key)
+
+	return nil
+}
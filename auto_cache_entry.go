@@ -14,7 +14,8 @@ type EntryAutoCache struct {
 	updater  func() (interface{}, error)
 	interval time.Duration
 	run      bool
-	ticker   *time.Ticker
+	clock    Clock
+	ticker   Ticker
 	mutex    sync.RWMutex
 	signals  chan struct{}
 	logger   IAutoCacheLogger
@@ -27,10 +28,16 @@ func CreateEntryAutoCache(updater func() (interface{}, error), interval time.Dur
 		run:      false,
 		updater:  updater,
 		interval: interval,
+		clock:    RealClock,
 		logger:   logger,
 	}
 }
 
+// SetClock changes clock driving updates, it has to be called before Start
+func (entry *EntryAutoCache) SetClock(clock Clock) {
+	entry.clock = clock
+}
+
 // GetValue returns the result of processing the updater
 func (entry *EntryAutoCache) GetValue() (interface{}, error) {
 	entry.mutex.RLock()
@@ -60,7 +67,7 @@ func (entry *EntryAutoCache) Start() error {
 	}
 	entry.run = true
 	entry.signals = make(chan struct{}, 1)
-	entry.ticker = time.NewTicker(entry.interval)
+	entry.ticker = entry.clock.NewTicker(entry.interval)
 	if err := entry.process(); err != nil {
 		return err
 	}
@@ -111,7 +118,7 @@ func (entry *EntryAutoCache) process() error {
 func (entry *EntryAutoCache) loop() {
 	for {
 		select {
-		case <-entry.ticker.C:
+		case <-entry.ticker.C():
 			entry.process()
 		case <-entry.signals:
 			return
@@ -1,6 +1,7 @@
 package cache
 
 import (
+	"context"
 	"sync"
 	"time"
 
@@ -14,6 +15,8 @@ type StorageAutoCacheFake struct {
 	mutex    sync.RWMutex
 }
 
+var _ IAutoCache = &StorageAutoCacheFake{} // StorageAutoCacheFake implements IAutoCache
+
 // NewStorageAutoCache create new instance of StorageAutoCache
 func NewStorageAutoCacheFake() *StorageAutoCacheFake {
 	return &StorageAutoCacheFake{
@@ -48,3 +51,32 @@ func (storage *StorageAutoCacheFake) Put(updater func() (interface{}, error), ke
 
 	return nil
 }
+
+// GetCtx returns data by given key unless context is done
+func (storage *StorageAutoCacheFake) GetCtx(ctx context.Context, key string) (interface{}, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	return storage.Get(key)
+}
+
+// PutCtx puts data into storage unless context is done
+func (storage *StorageAutoCacheFake) PutCtx(ctx context.Context, updater func() (interface{}, error), key string, ttl time.Duration) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	return storage.Put(updater, key, ttl)
+}
+
+// RemoveCtx removes value by key unless context is done
+func (storage *StorageAutoCacheFake) RemoveCtx(ctx context.Context, key string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	storage.Remove(key)
+
+	return nil
+}
@@ -1,12 +1,18 @@
 package cache
 
-import "time"
+import (
+	"context"
+	"time"
+)
 
 // IAutoCache defines required interface for caching module (need for auto cache)
 type IAutoCache interface {
 	Get(key string) (data interface{}, err error)
 	Put(updater func() (interface{}, error), key string, ttl time.Duration) error
 	Remove(key string)
+	GetCtx(ctx context.Context, key string) (data interface{}, err error)
+	PutCtx(ctx context.Context, updater func() (interface{}, error), key string, ttl time.Duration) error
+	RemoveCtx(ctx context.Context, key string) error
 }
 
 type IAutoCacheLogger interface {
@@ -1,6 +1,7 @@
 package cache
 
 import (
+	"context"
 	"net"
 	"strconv"
 	"sync"
@@ -223,16 +224,29 @@ func (a *AerospikeCache) SetCachePrefix(prefix string) {
 
 // Get returns data by given key
 func (a *AerospikeCache) Get(key *Key) ([]byte, bool) {
+	buf, ok, _ := a.GetCtx(context.Background(), key)
 
+	return buf, ok
+}
+
+// GetCtx returns data by given key, read timeout is limited by context deadline
+func (a *AerospikeCache) GetCtx(ctx context.Context, key *Key) ([]byte, bool, error) {
+	buf, _, ok, err := a.get(ctx, key)
+
+	return buf, ok, err
+}
+
+func (a *AerospikeCache) get(ctx context.Context, key *Key) ([]byte, time.Duration, bool, error) {
 	ts := time.Now()
 	var (
 		ok   bool
 		node *aerospike.Node
 		buf  []byte
+		ttl  time.Duration
 		err  error
 	)
 
-	buf, node, ok, err = a.getByPk(key.Set, key.Pk)
+	buf, ttl, node, ok, err = a.getByPk(ctx, key.Set, key.Pk)
 
 	a.updateHitOrMissCount(ok, map[string]string{
 		metric.LabelHost:      a.getNodeHostName(node),
@@ -248,7 +262,7 @@ func (a *AerospikeCache) Get(key *Key) ([]byte, bool) {
 		metric.LabelIsError:   metric.IsError(err),
 	}, metric.SinceMs(ts))
 
-	return buf, ok
+	return buf, ttl, ok, err
 }
 
 func (a *AerospikeCache) updateHitOrMissCount(condition bool, labels map[string]string) {
@@ -260,7 +274,7 @@ func (a *AerospikeCache) updateHitOrMissCount(condition bool, labels map[string]
 	}
 }
 
-func (a *AerospikeCache) getByPk(set, pk string) ([]byte, *aerospike.Node, bool, error) {
+func (a *AerospikeCache) getByPk(ctx context.Context, set, pk string) ([]byte, time.Duration, *aerospike.Node, bool, error) {
 	var (
 		data []byte
 		ok   bool
@@ -270,18 +284,23 @@ func (a *AerospikeCache) getByPk(set, pk string) ([]byte, *aerospike.Node, bool,
 	key, err := a.createKey(set, pk)
 	if err != nil {
 		a.logger.Warning(err.Error())
-		return data, nil, ok, err
+		return data, 0, nil, ok, err
+	}
+
+	policy := *a.getPolicy
+	if policy.Timeout, err = contextTimeout(ctx, policy.Timeout); err != nil {
+		return data, 0, nil, ok, err
 	}
 
-	rec, err := a.client.Get(a.getPolicy, key, dataBin)
+	rec, err := a.client.Get(&policy, key, dataBin)
 
 	if err != nil {
 		a.logger.Warningf("could not get data for set '%s' by primary key '%s', error: %q", set, pk, err.Error())
-		return data, nil, ok, err
+		return data, 0, nil, ok, err
 	}
 
 	if rec == nil {
-		return data, nil, ok, err
+		return data, 0, nil, ok, err
 	}
 
 	var bin interface{}
@@ -290,22 +309,27 @@ func (a *AerospikeCache) getByPk(set, pk string) ([]byte, *aerospike.Node, bool,
 		data, ok = bin.([]byte)
 	}
 
-	return data, rec.Node, ok, err
+	return data, time.Duration(rec.Expiration) * time.Second, rec.Node, ok, err
 }
 
 // Put will delayed put cache in Aerospike
 func (a *AerospikeCache) Put(data []byte, key *Key, ttl time.Duration) {
-	a.put(data, key, ttl)
+	a.put(context.Background(), data, key, ttl)
 }
 
-func (a *AerospikeCache) put(data []byte, key *Key, ttl time.Duration) {
+// PutCtx puts data into Aerospike, write timeout is limited by context deadline
+func (a *AerospikeCache) PutCtx(ctx context.Context, data []byte, key *Key, ttl time.Duration) error {
+	return a.put(ctx, data, key, ttl)
+}
+
+func (a *AerospikeCache) put(ctx context.Context, data []byte, key *Key, ttl time.Duration) error {
 	ts := time.Now()
 	var err error
 
 	if len(key.Tags) == 0 {
-		err = a.putByPk(data, key.Set, key.Pk, ttl)
+		err = a.putByPk(ctx, data, key.Set, key.Pk, ttl)
 	} else {
-		err = a.putByPkAndTags(data, key.Set, key.Pk, key.Tags, ttl)
+		err = a.putByPkAndTags(ctx, data, key.Set, key.Pk, key.Tags, ttl)
 	}
 
 	a.metric.ObserveRT(map[string]string{
@@ -314,9 +338,11 @@ func (a *AerospikeCache) put(data []byte, key *Key, ttl time.Duration) {
 		metric.LabelOperation: "put",
 		metric.LabelIsError:   metric.IsError(err),
 	}, metric.SinceMs(ts))
+
+	return err
 }
 
-func (a *AerospikeCache) putByPk(data []byte, set, pk string, ttl time.Duration) error {
+func (a *AerospikeCache) putByPk(ctx context.Context, data []byte, set, pk string, ttl time.Duration) error {
 	aeroKey, err := a.createKey(set, pk)
 	if err != nil {
 		a.logger.Warning(err.Error())
@@ -328,6 +354,9 @@ func (a *AerospikeCache) putByPk(data []byte, set, pk string, ttl time.Duration)
 	}
 
 	policy := a.getWritePolice(ttl)
+	if policy.Timeout, err = contextTimeout(ctx, policy.Timeout); err != nil {
+		return err
+	}
 
 	if err = a.client.PutBins(policy, aeroKey, bins...); err != nil {
 		a.logger.Warningf("could not put into set '%s' by primary key '%s': %+v", set, pk, err)
@@ -336,7 +365,7 @@ func (a *AerospikeCache) putByPk(data []byte, set, pk string, ttl time.Duration)
 	return err
 }
 
-func (a *AerospikeCache) putByPkAndTags(data []byte, set, pk string, tags []string, ttl time.Duration) error {
+func (a *AerospikeCache) putByPkAndTags(ctx context.Context, data []byte, set, pk string, tags []string, ttl time.Duration) error {
 	var err error
 
 	// build composite primary key for quick data fetching
@@ -346,31 +375,44 @@ func (a *AerospikeCache) putByPkAndTags(data []byte, set, pk string, tags []stri
 		return err
 	}
 
-	prefTags := make([]string, len(tags))
-	for i := range prefTags {
-		prefTags[i] = a.cachePrefix + tags[i]
-	}
-
 	// add tagsBin to be able to invalidate cache by tag
 	bins := []*aerospike.Bin{
 		aerospike.NewBin(dataBin, data),
-		aerospike.NewBin(tagsBin, prefTags),
+		aerospike.NewBin(tagsBin, a.prefixedTags(tags)),
 	}
 
 	policy := a.getWritePolice(ttl)
 	if a.config.PutTimeout > 0 {
 		policy.Timeout = a.config.PutTimeout
 	}
+	if policy.Timeout, err = contextTimeout(ctx, policy.Timeout); err != nil {
+		return err
+	}
 
 	if err = a.client.PutBins(policy, aeroKey, bins...); err != nil {
 		a.logger.Warningf("could not put into set '%s' by primary key '%s': %+v", set, pk, err)
 	}
 
-	return nil
+	return err
+}
+
+// prefixedTags returns tags with cache prefix as they are stored in tagsBin
+func (a *AerospikeCache) prefixedTags(tags []string) []string {
+	prefTags := make([]string, len(tags))
+	for i := range prefTags {
+		prefTags[i] = a.cachePrefix + tags[i]
+	}
+
+	return prefTags
 }
 
 // ScanKeys return all keys for set
 func (a *AerospikeCache) ScanKeys(set string) ([]Key, error) {
+	return a.ScanKeysCtx(context.Background(), set)
+}
+
+// ScanKeysCtx return all keys for set, scan is stopped when context is done
+func (a *AerospikeCache) ScanKeysCtx(ctx context.Context, set string) ([]Key, error) {
 
 	// We do not know how many records will return
 	var keys []Key
@@ -379,6 +421,12 @@ func (a *AerospikeCache) ScanKeys(set string) ([]Key, error) {
 	policy.Priority = aerospike.LOW
 	policy.IncludeBinData = true
 
+	timeout, err := contextTimeout(ctx, policy.Timeout)
+	if err != nil {
+		return nil, err
+	}
+	policy.Timeout = timeout
+
 	// Works only with records containing the BINS `id` and `tags`
 	fields := []string{"id", "tags"}
 
@@ -396,8 +444,12 @@ func (a *AerospikeCache) ScanKeys(set string) ([]Key, error) {
 	if err != nil {
 		return nil, err
 	}
+	defer r.Close()
 
 	for v := range r.Records {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
 
 		// We can't use v.Key because v.Key.Value() is nil
 		if pkInterface, ok = v.Bins["id"]; !ok {
@@ -442,15 +494,20 @@ func (a *AerospikeCache) sliceInterfacesToString(src interface{}) []string {
 // If tags provided, all records having at least one of them will be removed
 // Otherwise only an item with given Primary key will be removed
 func (a *AerospikeCache) Remove(key *Key) (err error) {
+	return a.RemoveCtx(context.Background(), key)
+}
+
+// RemoveCtx works as Remove, timeouts are limited by context deadline
+func (a *AerospikeCache) RemoveCtx(ctx context.Context, key *Key) (err error) {
 	var ts = time.Now()
 
 	if len(key.Pk) > 0 {
-		err = a.removeByPk(key.Set, key.Pk)
+		err = a.removeByPk(ctx, key.Set, key.Pk)
 	}
 
 	if err == nil {
 		for _, tag := range key.Tags {
-			err = a.removeByTag(key.Set, tag)
+			err = a.removeByTag(ctx, key.Set, tag)
 			if err != nil {
 				break
 			}
@@ -467,13 +524,16 @@ func (a *AerospikeCache) Remove(key *Key) (err error) {
 	return
 }
 
-func (a *AerospikeCache) removeByPk(set, pk string) error {
+func (a *AerospikeCache) removeByPk(ctx context.Context, set, pk string) error {
 	aeroKey, err := a.createKey(set, pk)
 	if err != nil {
 		return err
 	}
 
 	writePolicy := a.getWritePolice(time.Duration(0))
+	if writePolicy.Timeout, err = contextTimeout(ctx, writePolicy.Timeout); err != nil {
+		return err
+	}
 
 	if _, err = a.client.Delete(writePolicy, aeroKey); err != nil {
 		err = errors.Wrapf(err, "could not remove from set '%s' by primary key '%s'", set, pk)
@@ -483,9 +543,7 @@ func (a *AerospikeCache) removeByPk(set, pk string) error {
 }
 
 // removeByTag removes data from Aerospike by PK and tags
-func (a *AerospikeCache) removeByTag(set, tag string) error {
-	var err error
-
+func (a *AerospikeCache) removeByTag(ctx context.Context, set, tag string) (err error) {
 	defer func() {
 		// once in a while query returns a key with empty digest hash
 		// then delete command panics with given key (aerospike client bug?)
@@ -495,11 +553,16 @@ func (a *AerospikeCache) removeByTag(set, tag string) error {
 	}()
 
 	queryPolicy := aerospike.NewQueryPolicy()
-	queryPolicy.Timeout = a.config.RemoveTimeout
 	queryPolicy.MaxRetries = a.config.MaxRetries
 	queryPolicy.WaitUntilMigrationsAreOver = true
+	if queryPolicy.Timeout, err = contextTimeout(ctx, a.config.RemoveTimeout); err != nil {
+		return err
+	}
 
 	writePolicy := a.getWritePolice(time.Duration(0))
+	if writePolicy.Timeout, err = contextTimeout(ctx, writePolicy.Timeout); err != nil {
+		return err
+	}
 
 	stm := aerospike.NewStatement(a.ns, set)
 	stm.Addfilter(aerospike.NewContainsFilter(tagsBin, aerospike.ICT_LIST, a.cachePrefix+tag))
@@ -513,6 +576,9 @@ func (a *AerospikeCache) removeByTag(set, tag string) error {
 	ch := recordSet.Results()
 
 	for d := range ch {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
 
 		if d.Err != nil {
 			a.logger.Critical(d.Err.Error())
@@ -541,6 +607,25 @@ func (a *AerospikeCache) removeByTag(set, tag string) error {
 	return err
 }
 
+// contextTimeout returns timeout limited by context deadline, zero timeout means no limit
+func contextTimeout(ctx context.Context, timeout time.Duration) (time.Duration, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	if deadline, ok := ctx.Deadline(); ok {
+		left := time.Until(deadline)
+		if left <= 0 {
+			return 0, context.DeadlineExceeded
+		}
+		if timeout <= 0 || left < timeout {
+			timeout = left
+		}
+	}
+
+	return timeout, nil
+}
+
 // Close cache storage Aerospike
 func (a *AerospikeCache) Close() {
 	// send quit message to updateConnectionCountMetric goroutine
@@ -562,16 +647,38 @@ func (a *AerospikeCache) Count() int {
 
 // ClearSet removes all values in set
 func (a *AerospikeCache) ClearSet(set string) error {
-	result, err := a.client.ScanAll(nil, a.ns, set)
+	return a.ClearSetCtx(context.Background(), set)
+}
+
+// ClearSetCtx removes all values in set, it is stopped when context is done
+func (a *AerospikeCache) ClearSetCtx(ctx context.Context, set string) error {
+	scanPolicy := aerospike.NewScanPolicy()
+	timeout, err := contextTimeout(ctx, scanPolicy.Timeout)
+	if err != nil {
+		return err
+	}
+	scanPolicy.Timeout = timeout
+
+	result, err := a.client.ScanAll(scanPolicy, a.ns, set)
 	if nil != err {
 		return err
 	}
+	defer result.Close()
+
 	for record := range result.Results() {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
 		if record.Err != nil {
 			a.logger.Warningf("Record error while clearing set %s: %s", set, err)
 			continue
 		}
-		if _, err := a.client.Delete(nil, record.Record.Key); err != nil {
+
+		deletePolicy := aerospike.NewWritePolicy(0, 0)
+		if deletePolicy.Timeout, err = contextTimeout(ctx, deletePolicy.Timeout); err != nil {
+			return err
+		}
+		if _, err := a.client.Delete(deletePolicy, record.Record.Key); err != nil {
 			return errors.Wrapf(err, "could not remove from set '%s'", set)
 		}
 	}
@@ -0,0 +1,135 @@
+package cache
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/aerospike/aerospike-client-go"
+
+	"go-cache/metric"
+)
+
+// batchWriteConcurrency is max number of parallel writes of one batch
+const batchWriteConcurrency = 16
+
+// GetMulti returns data by keys with one BatchGet request. Results are keyed by Key.ID()
+func (a *AerospikeCache) GetMulti(keys []*Key) map[string]ByteResult {
+	ts := time.Now()
+	results := make(map[string]ByteResult, len(keys))
+
+	aeroKeys := make([]*aerospike.Key, 0, len(keys))
+	batchKeys := make([]*Key, 0, len(keys))
+	for _, key := range keys {
+		aeroKey, err := a.createKey(key.Set, key.Pk)
+		if err != nil {
+			a.logger.Warning(err.Error())
+			results[key.ID()] = ByteResult{Err: err}
+			continue
+		}
+		aeroKeys = append(aeroKeys, aeroKey)
+		batchKeys = append(batchKeys, key)
+	}
+
+	var (
+		records []*aerospike.Record
+		err     error
+	)
+	if len(aeroKeys) > 0 {
+		records, err = a.client.BatchGet(a.getPolicy, aeroKeys, dataBin)
+		if err != nil {
+			a.logger.Warningf("could not get batch of %d keys, error: %q", len(aeroKeys), err.Error())
+		}
+	}
+
+	for i, key := range batchKeys {
+		var result ByteResult
+
+		switch {
+		case err != nil:
+			result.Err = err
+		case i < len(records) && records[i] != nil:
+			result.Data, result.Found = records[i].Bins[dataBin].([]byte)
+		}
+		results[key.ID()] = result
+
+		if err == nil {
+			a.updateHitOrMissCount(result.Found, map[string]string{
+				metric.LabelNamespace: a.ns,
+				metric.LabelSet:       key.Set,
+			})
+		}
+	}
+
+	a.metric.ObserveRT(map[string]string{
+		metric.LabelNamespace: a.ns,
+		metric.LabelSet:       batchSet(keys),
+		metric.LabelOperation: "get_multi",
+		metric.LabelIsError:   metric.IsError(err),
+	}, metric.SinceMs(ts))
+
+	return results
+}
+
+// PutMulti puts data by keys with parallel writes. Returns errors of failed keys keyed by Key.ID()
+func (a *AerospikeCache) PutMulti(items []ByteItem, ttl time.Duration) map[string]error {
+	keys := make([]*Key, len(items))
+	for i := range items {
+		keys[i] = items[i].Key
+	}
+
+	return a.multi("put_multi", keys, func(i int) error {
+		return a.put(context.Background(), items[i].Data, items[i].Key, ttl)
+	})
+}
+
+// RemoveMulti removes data by keys with parallel deletes. Returns errors of failed keys keyed by Key.ID()
+func (a *AerospikeCache) RemoveMulti(keys []*Key) map[string]error {
+	return a.multi("remove_multi", keys, func(i int) error {
+		return a.Remove(keys[i])
+	})
+}
+
+// multi runs op for every key in parallel and reports latency of the whole batch
+func (a *AerospikeCache) multi(operation string, keys []*Key, op func(i int) error) map[string]error {
+	ts := time.Now()
+
+	var (
+		errs = make(map[string]error)
+		lock sync.Mutex
+		wg   sync.WaitGroup
+		sem  = make(chan struct{}, batchWriteConcurrency)
+	)
+
+	for i := range keys {
+		wg.Add(1)
+		sem <- struct{}{}
+
+		go func(i int) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			if err := op(i); err != nil {
+				lock.Lock()
+				errs[keys[i].ID()] = err
+				lock.Unlock()
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	a.metric.ObserveRT(map[string]string{
+		metric.LabelNamespace: a.ns,
+		metric.LabelSet:       batchSet(keys),
+		metric.LabelOperation: operation,
+		metric.LabelIsError:   metric.IsError(firstError(errs)),
+	}, metric.SinceMs(ts))
+
+	if len(errs) == 0 {
+		return nil
+	}
+
+	return errs
+}
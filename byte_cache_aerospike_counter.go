@@ -0,0 +1,70 @@
+package cache
+
+import (
+	"time"
+
+	"github.com/aerospike/aerospike-client-go"
+
+	"go-cache/errors"
+	"go-cache/metric"
+)
+
+// counterBin keeps value of counter record
+const counterBin = "counter"
+
+// Incr atomically adds delta to counter by key on server side and returns new value. Missing counter starts at zero,
+// ttl counts from the last change. Counter is kept in its own bin, so Get does not return it
+func (a *AerospikeCache) Incr(key *Key, delta int64, ttl time.Duration) (int64, error) {
+	ts := time.Now()
+
+	counter, err := a.incr(key, delta, ttl)
+
+	a.metric.ObserveRT(map[string]string{
+		metric.LabelNamespace: a.ns,
+		metric.LabelSet:       key.Set,
+		metric.LabelOperation: "incr",
+		metric.LabelIsError:   metric.IsError(err),
+	}, metric.SinceMs(ts))
+
+	return counter, err
+}
+
+// Decr atomically subtracts delta from counter by key on server side and returns new value
+func (a *AerospikeCache) Decr(key *Key, delta int64, ttl time.Duration) (int64, error) {
+	return a.Incr(key, -delta, ttl)
+}
+
+func (a *AerospikeCache) incr(key *Key, delta int64, ttl time.Duration) (int64, error) {
+	aeroKey, err := a.createKey(key.Set, key.Pk)
+	if err != nil {
+		a.logger.Warning(err.Error())
+		return 0, err
+	}
+
+	ops := []*aerospike.Operation{
+		aerospike.AddOp(aerospike.NewBin(counterBin, delta)),
+		aerospike.GetOpForBin(counterBin),
+	}
+	if len(key.Tags) > 0 {
+		ops = append(ops, aerospike.PutOp(aerospike.NewBin(tagsBin, a.prefixedTags(key.Tags))))
+	}
+
+	// operations with reads are not allowed to replace record
+	policy := a.getWritePolice(ttl)
+	policy.RecordExistsAction = aerospike.UPDATE
+
+	rec, err := a.client.Operate(policy, aeroKey, ops...)
+	if err != nil {
+		a.logger.Warningf("could not increment counter of set '%s' by primary key '%s': %+v", key.Set, key.Pk, err)
+		return 0, err
+	}
+
+	switch counter := rec.Bins[counterBin].(type) {
+	case int:
+		return int64(counter), nil
+	case int64:
+		return counter, nil
+	}
+
+	return 0, errors.Errorf("unexpected counter of set '%s' by primary key '%s': %v", key.Set, key.Pk, rec.Bins[counterBin])
+}
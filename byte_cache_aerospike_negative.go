@@ -0,0 +1,107 @@
+package cache
+
+import (
+	"time"
+
+	"github.com/aerospike/aerospike-client-go"
+
+	"go-cache/metric"
+)
+
+// negativeBin marks record of key known to be absent
+const negativeBin = "negative"
+
+// PutNegative puts negative record, the key is known to be absent for ttl.
+// Get reports it as miss, Lookup reports it as LookupAbsent
+func (a *AerospikeCache) PutNegative(key *Key, ttl time.Duration) error {
+	ts := time.Now()
+
+	err := a.putNegative(key, ttl)
+
+	a.metric.ObserveRT(map[string]string{
+		metric.LabelNamespace: a.ns,
+		metric.LabelSet:       key.Set,
+		metric.LabelOperation: "put_negative",
+		metric.LabelIsError:   metric.IsError(err),
+	}, metric.SinceMs(ts))
+
+	return err
+}
+
+func (a *AerospikeCache) putNegative(key *Key, ttl time.Duration) error {
+	aeroKey, err := a.createKey(key.Set, key.Pk)
+	if err != nil {
+		a.logger.Warning(err.Error())
+		return err
+	}
+
+	bins := []*aerospike.Bin{
+		aerospike.NewBin(negativeBin, 1),
+	}
+	if len(key.Tags) > 0 {
+		bins = append(bins, aerospike.NewBin(tagsBin, a.prefixedTags(key.Tags)))
+	}
+
+	// record is replaced, so data of the key is removed
+	policy := a.getWritePolice(ttl)
+	if err = a.client.PutBins(policy, aeroKey, bins...); err != nil {
+		a.logger.Warningf("could not put negative record into set '%s' by primary key '%s': %+v", key.Set, key.Pk, err)
+	}
+
+	return err
+}
+
+// Lookup returns data by given key and tells whether it is hit, miss or known absence
+func (a *AerospikeCache) Lookup(key *Key) ([]byte, LookupResult) {
+	ts := time.Now()
+
+	var (
+		data   []byte
+		node   *aerospike.Node
+		rec    *aerospike.Record
+		result = LookupMiss
+	)
+
+	aeroKey, err := a.createKey(key.Set, key.Pk)
+	if err == nil {
+		rec, err = a.client.Get(a.getPolicy, aeroKey, dataBin, negativeBin)
+	}
+	if err != nil {
+		a.logger.Warningf("could not get data for set '%s' by primary key '%s', error: %q", key.Set, key.Pk, err.Error())
+	}
+
+	if rec != nil {
+		node = rec.Node
+
+		var ok bool
+		if data, ok = rec.Bins[dataBin].([]byte); ok {
+			result = LookupHit
+		} else if _, ok = rec.Bins[negativeBin]; ok {
+			result = LookupAbsent
+		}
+	}
+
+	labels := map[string]string{
+		metric.LabelHost:      a.getNodeHostName(node),
+		metric.LabelNamespace: a.ns,
+		metric.LabelSet:       key.Set,
+	}
+	switch result {
+	case LookupHit:
+		a.metric.RegisterHit(labels)
+	case LookupMiss:
+		a.metric.RegisterMiss(labels)
+	case LookupAbsent:
+		a.metric.RegisterNegativeHit(labels)
+	}
+
+	a.metric.ObserveRT(map[string]string{
+		metric.LabelHost:      a.getNodeHostName(node),
+		metric.LabelNamespace: a.ns,
+		metric.LabelSet:       key.Set,
+		metric.LabelOperation: "lookup",
+		metric.LabelIsError:   metric.IsError(err),
+	}, metric.SinceMs(ts))
+
+	return data, result
+}
@@ -1,6 +1,7 @@
 package cache
 
 import (
+	"context"
 	"fmt"
 	"path"
 	"runtime"
@@ -121,6 +122,103 @@ func TestByteCacheAerospike_GetExpired(t *testing.T) {
 	assertByteCacheKeyEmpty(t, cache, &key)
 }
 
+func TestByteCacheAerospike_Multi(t *testing.T) {
+	cache := initAerospikeByteCache(t, "multi")
+
+	key1 := &Key{Set: "testset", Pk: "test_multi1"}
+	key2 := &Key{Set: "testset", Pk: "test_multi2"}
+	missing := &Key{Set: "testset", Pk: "test_multi_missing"}
+
+	errs := cache.PutMulti([]ByteItem{
+		{Key: key1, Data: []byte("multi1")},
+		{Key: key2, Data: []byte("multi2")},
+	}, DefaultCacheTTL)
+	if len(errs) != 0 {
+		t.Fatalf("Unexpected errors: %v", errs)
+	}
+
+	results := cache.GetMulti([]*Key{key1, key2, missing})
+	if r := results[key1.ID()]; !r.Found || string(r.Data) != "multi1" {
+		t.Errorf("Unexpected result for %s: %v", key1.ID(), r)
+	}
+	if r := results[key2.ID()]; !r.Found || string(r.Data) != "multi2" {
+		t.Errorf("Unexpected result for %s: %v", key2.ID(), r)
+	}
+	if r := results[missing.ID()]; r.Found || r.Err != nil {
+		t.Errorf("Unexpected result for %s: %v", missing.ID(), r)
+	}
+
+	if errs := cache.RemoveMulti([]*Key{key1, key2}); len(errs) != 0 {
+		t.Fatalf("Unexpected errors: %v", errs)
+	}
+	assertByteCacheKeyEmpty(t, cache, key1)
+	assertByteCacheKeyEmpty(t, cache, key2)
+}
+
+func TestByteCacheAerospike_Touch(t *testing.T) {
+	cache := initAerospikeByteCache(t, "touch")
+
+	key := &Key{Set: "testset", Pk: "test_touch"}
+	cache.Put([]byte("touch"), key, time.Second)
+
+	ok, err := cache.Touch(key, DefaultCacheTTL)
+	if err != nil || !ok {
+		t.Fatalf("Unexpected touch result %v, %v", ok, err)
+	}
+
+	time.Sleep(time.Second * 2)
+
+	assertByteCacheKeyHasValue(t, cache, key, "touch")
+	if ttl, ok := cache.TTL(key); !ok || ttl <= time.Second {
+		t.Errorf("Unexpected TTL %s, %v", ttl, ok)
+	}
+	if data, ttl, ok, err := cache.GetWithTTLCtx(context.Background(), key); err != nil || !ok || string(data) != "touch" || ttl <= time.Second {
+		t.Errorf("Unexpected get with TTL result %q, %s, %v, %v", data, ttl, ok, err)
+	}
+
+	missing := &Key{Set: "testset", Pk: "test_touch_missing"}
+	if ok, err := cache.Touch(missing, DefaultCacheTTL); err != nil || ok {
+		t.Errorf("Unexpected touch result of missing key %v, %v", ok, err)
+	}
+}
+
+func TestByteCacheAerospike_CompareAndSwap(t *testing.T) {
+	cache := initAerospikeByteCache(t, "cas")
+
+	key := &Key{Set: "testset", Pk: "test_cas"}
+	cache.Remove(key)
+
+	if err := cache.CompareAndSwap(key, 0, []byte("first"), DefaultCacheTTL); err != nil {
+		t.Fatal(err)
+	}
+
+	data, version, ok := cache.GetVersioned(key)
+	if !ok || string(data) != "first" || version == 0 {
+		t.Fatalf("Unexpected result %q, %d, %v", data, version, ok)
+	}
+
+	cache.Put([]byte("concurrent"), key, DefaultCacheTTL)
+
+	if err := cache.CompareAndSwap(key, version, []byte("second"), DefaultCacheTTL); err != ErrVersionConflict {
+		t.Errorf("Expected version conflict, got %v", err)
+	}
+	assertByteCacheKeyHasValue(t, cache, key, "concurrent")
+}
+
+func TestByteCacheAerospike_Incr(t *testing.T) {
+	cache := initAerospikeByteCache(t, "incr")
+
+	key := &Key{Set: "testset", Pk: "test_incr"}
+	cache.Remove(key)
+
+	if value, err := cache.Incr(key, 5, DefaultCacheTTL); err != nil || value != 5 {
+		t.Fatalf("Missing counter should start at zero, got %d, %v", value, err)
+	}
+	if value, err := cache.Decr(key, 2, DefaultCacheTTL); err != nil || value != 3 {
+		t.Errorf("Unexpected counter %d, %v", value, err)
+	}
+}
+
 func TestByteCacheAerospike_Remove(t *testing.T) {
 	cache := initAerospikeByteCache(t, "remove")
 
@@ -317,3 +415,28 @@ func callerInfo() string {
 
 	return fileNameLine
 }
+
+func TestContextTimeout(t *testing.T) {
+	timeout, err := contextTimeout(context.Background(), time.Second)
+	if err != nil || timeout != time.Second {
+		t.Errorf("Unexpected timeout %s, %v", timeout, err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	timeout, err = contextTimeout(ctx, time.Second)
+	if err != nil || timeout > 100*time.Millisecond || timeout <= 0 {
+		t.Errorf("Timeout should be limited by deadline, got %s, %v", timeout, err)
+	}
+
+	timeout, err = contextTimeout(ctx, 0)
+	if err != nil || timeout > 100*time.Millisecond || timeout <= 0 {
+		t.Errorf("Zero timeout should be limited by deadline, got %s, %v", timeout, err)
+	}
+
+	cancel()
+	if _, err := contextTimeout(ctx, time.Second); err != context.Canceled {
+		t.Errorf("Expected canceled error, got %v", err)
+	}
+}
@@ -0,0 +1,106 @@
+package cache
+
+import (
+	"context"
+	"time"
+
+	"github.com/aerospike/aerospike-client-go"
+	"github.com/aerospike/aerospike-client-go/types"
+
+	"go-cache/errors"
+	"go-cache/metric"
+)
+
+// TTL returns remaining time to live of record by key, it is read from record header
+func (a *AerospikeCache) TTL(key *Key) (time.Duration, bool) {
+	ts := time.Now()
+
+	var (
+		node *aerospike.Node
+		rec  *aerospike.Record
+	)
+
+	aeroKey, err := a.createKey(key.Set, key.Pk)
+	if err == nil {
+		rec, err = a.client.GetHeader(a.getPolicy, aeroKey)
+	}
+	if err != nil {
+		a.logger.Warningf("could not get header for set '%s' by primary key '%s', error: %q", key.Set, key.Pk, err.Error())
+	}
+	if rec != nil {
+		node = rec.Node
+	}
+
+	a.metric.ObserveRT(map[string]string{
+		metric.LabelHost:      a.getNodeHostName(node),
+		metric.LabelNamespace: a.ns,
+		metric.LabelSet:       key.Set,
+		metric.LabelOperation: "ttl",
+		metric.LabelIsError:   metric.IsError(err),
+	}, metric.SinceMs(ts))
+
+	if rec == nil {
+		return 0, false
+	}
+
+	return time.Duration(rec.Expiration) * time.Second, true
+}
+
+// GetWithTTLCtx returns data by key with remaining time to live read from the same record,
+// read timeout is limited by context deadline
+func (a *AerospikeCache) GetWithTTLCtx(ctx context.Context, key *Key) ([]byte, time.Duration, bool, error) {
+	return a.get(ctx, key)
+}
+
+// Touch extends life of record by key to ttl from now without rewriting its bins.
+// Returns false if there is no such record
+func (a *AerospikeCache) Touch(key *Key, ttl time.Duration) (bool, error) {
+	ts := time.Now()
+
+	ok, err := a.touch(key, ttl)
+
+	a.metric.ObserveRT(map[string]string{
+		metric.LabelNamespace: a.ns,
+		metric.LabelSet:       key.Set,
+		metric.LabelOperation: "touch",
+		metric.LabelIsError:   metric.IsError(err),
+	}, metric.SinceMs(ts))
+
+	return ok, err
+}
+
+func (a *AerospikeCache) touch(key *Key, ttl time.Duration) (bool, error) {
+	aeroKey, err := a.createKey(key.Set, key.Pk)
+	if err != nil {
+		a.logger.Warning(err.Error())
+		return false, err
+	}
+
+	policy := a.getWritePolice(ttl)
+	policy.RecordExistsAction = aerospike.UPDATE_ONLY
+
+	err = a.client.Touch(policy, aeroKey)
+	if aerospikeError, ok := err.(types.AerospikeError); ok && aerospikeError.ResultCode() == types.KEY_NOT_FOUND_ERROR {
+		return false, nil
+	}
+	if err != nil {
+		a.logger.Warningf("could not touch record of set '%s' by primary key '%s': %+v", key.Set, key.Pk, err)
+		return false, err
+	}
+
+	return true, nil
+}
+
+// PutUntil puts data which expires at given time. Aerospike counts TTL in seconds,
+// so data lives at least one second
+func (a *AerospikeCache) PutUntil(data []byte, key *Key, until time.Time) error {
+	ttl := until.Sub(time.Now())
+	if ttl <= 0 {
+		return errors.Errorf("could not put into set '%s' by primary key '%s': end of life %s has passed", key.Set, key.Pk, until)
+	}
+	if ttl < time.Second {
+		ttl = time.Second
+	}
+
+	return a.put(context.Background(), data, key, ttl)
+}
@@ -0,0 +1,107 @@
+package cache
+
+import (
+	"time"
+
+	"github.com/aerospike/aerospike-client-go"
+	"github.com/aerospike/aerospike-client-go/types"
+
+	"go-cache/metric"
+)
+
+// GetVersioned returns data by key with generation of its record. Version is 0 if there is no record,
+// negative record has version, though it is not returned as data
+func (a *AerospikeCache) GetVersioned(key *Key) ([]byte, uint64, bool) {
+	ts := time.Now()
+
+	var (
+		data    []byte
+		version uint64
+		ok      bool
+		node    *aerospike.Node
+		rec     *aerospike.Record
+	)
+
+	aeroKey, err := a.createKey(key.Set, key.Pk)
+	if err == nil {
+		rec, err = a.client.Get(a.getPolicy, aeroKey, dataBin)
+	}
+	if err != nil {
+		a.logger.Warningf("could not get data for set '%s' by primary key '%s', error: %q", key.Set, key.Pk, err.Error())
+	}
+
+	if rec != nil {
+		node = rec.Node
+		version = uint64(rec.Generation)
+		data, ok = rec.Bins[dataBin].([]byte)
+	}
+
+	a.updateHitOrMissCount(ok, map[string]string{
+		metric.LabelHost:      a.getNodeHostName(node),
+		metric.LabelNamespace: a.ns,
+		metric.LabelSet:       key.Set,
+	})
+
+	a.metric.ObserveRT(map[string]string{
+		metric.LabelHost:      a.getNodeHostName(node),
+		metric.LabelNamespace: a.ns,
+		metric.LabelSet:       key.Set,
+		metric.LabelOperation: "get_versioned",
+		metric.LabelIsError:   metric.IsError(err),
+	}, metric.SinceMs(ts))
+
+	return data, version, ok
+}
+
+// CompareAndSwap puts data only if generation of record is still equal to version returned by GetVersioned,
+// version 0 puts data only if there is no record. Returns ErrVersionConflict otherwise
+func (a *AerospikeCache) CompareAndSwap(key *Key, version uint64, data []byte, ttl time.Duration) error {
+	ts := time.Now()
+
+	err := a.compareAndSwap(key, version, data, ttl)
+
+	a.metric.ObserveRT(map[string]string{
+		metric.LabelNamespace: a.ns,
+		metric.LabelSet:       key.Set,
+		metric.LabelOperation: "cas",
+		metric.LabelIsError:   metric.IsError(err),
+	}, metric.SinceMs(ts))
+
+	return err
+}
+
+func (a *AerospikeCache) compareAndSwap(key *Key, version uint64, data []byte, ttl time.Duration) error {
+	aeroKey, err := a.createKey(key.Set, key.Pk)
+	if err != nil {
+		a.logger.Warning(err.Error())
+		return err
+	}
+
+	bins := []*aerospike.Bin{
+		aerospike.NewBin(dataBin, data),
+	}
+	if len(key.Tags) > 0 {
+		bins = append(bins, aerospike.NewBin(tagsBin, a.prefixedTags(key.Tags)))
+	}
+
+	policy := a.getWritePolice(ttl)
+	if version == 0 {
+		policy.RecordExistsAction = aerospike.CREATE_ONLY
+	} else {
+		policy.GenerationPolicy = aerospike.EXPECT_GEN_EQUAL
+		policy.Generation = uint32(version)
+	}
+
+	err = a.client.PutBins(policy, aeroKey, bins...)
+	if aerospikeError, ok := err.(types.AerospikeError); ok {
+		switch aerospikeError.ResultCode() {
+		case types.GENERATION_ERROR, types.KEY_EXISTS_ERROR, types.KEY_NOT_FOUND_ERROR:
+			return ErrVersionConflict
+		}
+	}
+	if err != nil {
+		a.logger.Warningf("could not put into set '%s' by primary key '%s': %+v", key.Set, key.Pk, err)
+	}
+
+	return err
+}
@@ -1,6 +1,9 @@
 package cache
 
-import "time"
+import (
+	"context"
+	"time"
+)
 
 // BlackholeCache implements cache that accept any data but always return null. Used for unit tests
 type BlackholeCache struct {
@@ -48,3 +51,103 @@ func (cache *BlackholeCache) Count() int {
 func (cache *BlackholeCache) ClearSet(set string) error {
 	return nil
 }
+
+// GetCtx returns nil, do nothing
+func (cache *BlackholeCache) GetCtx(ctx context.Context, key *Key) (data []byte, ok bool, err error) {
+	err = ctx.Err()
+	return
+}
+
+// PutCtx returns nil, do nothing
+func (cache *BlackholeCache) PutCtx(ctx context.Context, data []byte, key *Key, ttl time.Duration) error {
+	return ctx.Err()
+}
+
+// RemoveCtx returns nil, do nothing
+func (cache *BlackholeCache) RemoveCtx(ctx context.Context, key *Key) error {
+	return ctx.Err()
+}
+
+// ClearSetCtx returns nil, does nothing
+func (cache *BlackholeCache) ClearSetCtx(ctx context.Context, set string) error {
+	return ctx.Err()
+}
+
+// ScanKeysCtx returns nil, do nothing
+func (cache *BlackholeCache) ScanKeysCtx(ctx context.Context, set string) ([]Key, error) {
+	return nil, ctx.Err()
+}
+
+// GetMulti returns not found result for every key
+func (cache *BlackholeCache) GetMulti(keys []*Key) map[string]ByteResult {
+	results := make(map[string]ByteResult, len(keys))
+	for _, key := range keys {
+		results[key.ID()] = ByteResult{}
+	}
+
+	return results
+}
+
+// PutMulti returns nil, do nothing
+func (cache *BlackholeCache) PutMulti(items []ByteItem, ttl time.Duration) map[string]error {
+	return nil
+}
+
+// RemoveMulti returns nil, do nothing
+func (cache *BlackholeCache) RemoveMulti(keys []*Key) map[string]error {
+	return nil
+}
+
+// PutNegative returns nil, do nothing
+func (cache *BlackholeCache) PutNegative(key *Key, ttl time.Duration) error {
+	return nil
+}
+
+// Lookup returns miss, do nothing
+func (cache *BlackholeCache) Lookup(key *Key) ([]byte, LookupResult) {
+	return nil, LookupMiss
+}
+
+// TTL returns false, do nothing
+func (cache *BlackholeCache) TTL(key *Key) (ttl time.Duration, ok bool) {
+	return
+}
+
+// GetWithTTLCtx returns nil, do nothing
+func (cache *BlackholeCache) GetWithTTLCtx(ctx context.Context, key *Key) (data []byte, ttl time.Duration, ok bool, err error) {
+	err = ctx.Err()
+	return
+}
+
+// Touch returns false, do nothing
+func (cache *BlackholeCache) Touch(key *Key, ttl time.Duration) (ok bool, err error) {
+	return
+}
+
+// PutUntil returns nil, do nothing
+func (cache *BlackholeCache) PutUntil(data []byte, key *Key, until time.Time) error {
+	return nil
+}
+
+// GetVersioned returns nil, do nothing
+func (cache *BlackholeCache) GetVersioned(key *Key) (data []byte, version uint64, ok bool) {
+	return
+}
+
+// CompareAndSwap accepts only version 0 as there are no entries, do nothing
+func (cache *BlackholeCache) CompareAndSwap(key *Key, version uint64, data []byte, ttl time.Duration) error {
+	if version != 0 {
+		return ErrVersionConflict
+	}
+	return nil
+}
+
+// Incr returns delta as counters are not kept, do nothing
+func (cache *BlackholeCache) Incr(key *Key, delta int64, ttl time.Duration) (int64, error) {
+	return delta, nil
+}
+
+// Decr returns -delta as counters are not kept, do nothing
+func (cache *BlackholeCache) Decr(key *Key, delta int64, ttl time.Duration) (int64, error) {
+	return -delta, nil
+}
@@ -1,6 +1,9 @@
 package cache
 
-import "time"
+import (
+	"context"
+	"time"
+)
 
 // IByteCache defines required interface for caching module
 type IByteCache interface {
@@ -12,6 +15,37 @@ type IByteCache interface {
 	Close()
 	ClearSet(set string) error
 	ScanKeys(set string) ([]Key, error)
+	GetCtx(ctx context.Context, key *Key) (data []byte, ok bool, err error)
+	PutCtx(ctx context.Context, data []byte, key *Key, ttl time.Duration) error
+	RemoveCtx(ctx context.Context, key *Key) error
+	ClearSetCtx(ctx context.Context, set string) error
+	ScanKeysCtx(ctx context.Context, set string) ([]Key, error)
+	GetMulti(keys []*Key) map[string]ByteResult
+	PutMulti(items []ByteItem, ttl time.Duration) map[string]error
+	RemoveMulti(keys []*Key) map[string]error
+	PutNegative(key *Key, ttl time.Duration) error
+	Lookup(key *Key) (data []byte, result LookupResult)
+	TTL(key *Key) (ttl time.Duration, ok bool)
+	GetWithTTLCtx(ctx context.Context, key *Key) (data []byte, ttl time.Duration, ok bool, err error)
+	Touch(key *Key, ttl time.Duration) (ok bool, err error)
+	PutUntil(data []byte, key *Key, until time.Time) error
+	GetVersioned(key *Key) (data []byte, version uint64, ok bool)
+	CompareAndSwap(key *Key, version uint64, data []byte, ttl time.Duration) error
+	Incr(key *Key, delta int64, ttl time.Duration) (int64, error)
+	Decr(key *Key, delta int64, ttl time.Duration) (int64, error)
+}
+
+// ByteItem is data with its key for batch put
+type ByteItem struct {
+	Key  *Key
+	Data []byte
+}
+
+// ByteResult is result of batch get for one key
+type ByteResult struct {
+	Data  []byte
+	Found bool
+	Err   error
 }
 
 type IMemoryCacheLogger interface {
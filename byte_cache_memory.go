@@ -0,0 +1,297 @@
+package cache
+
+import (
+	"context"
+	"time"
+
+	"go-cache/metric"
+)
+
+// MemoryByteCache implements IByteCache in process memory on top of StructCache.
+// Data is copied on put and on get, so callers can reuse their buffers
+type MemoryByteCache struct {
+	cache  *StructCache
+	logger IMemoryCacheLogger
+}
+
+var _ IByteCache = &MemoryByteCache{} // MemoryByteCache implements IByteCache
+
+// NewMemoryByteCache initializes instance of MemoryByteCache, limit is max number of entries of every set
+func NewMemoryByteCache(limit int, logger IMemoryCacheLogger, metric metric.Metric) *MemoryByteCache {
+	if logger == nil {
+		logger = NewNilLogger()
+	}
+
+	return &MemoryByteCache{
+		cache:  NewStructCacheObject(limit, nil, metric),
+		logger: logger,
+	}
+}
+
+// SetDefaultCacheSetOptions changes options of sets, like default TTL used by puts with zero TTL
+func (cache *MemoryByteCache) SetDefaultCacheSetOptions(options CacheSetOptions) {
+	cache.cache.SetDefaultCacheSetOptions(options)
+}
+
+// SetClock changes source of time of the cache, sets created before keep previous clock
+func (cache *MemoryByteCache) SetClock(clock Clock) {
+	cache.cache.SetClock(clock)
+}
+
+// Get returns copy of data by key
+func (cache *MemoryByteCache) Get(key *Key) ([]byte, bool) {
+	data, ok := cache.cache.Get(key)
+
+	return bytesOf(data, ok)
+}
+
+// Put puts copy of data by key, errors are logged
+func (cache *MemoryByteCache) Put(data []byte, key *Key, ttl time.Duration) {
+	if err := cache.cache.Put(cloneBytes(data), key, ttl); err != nil {
+		cache.logger.Errorf("memory_cache: could not put into set '%s' by primary key '%s': %s", key.Set, key.Pk, err)
+	}
+}
+
+// Remove removes data by primary key and all data of the set having tags of the key
+func (cache *MemoryByteCache) Remove(key *Key) error {
+	cache.cache.Remove(key)
+
+	return nil
+}
+
+// Close stops collectors of expired data
+func (cache *MemoryByteCache) Close() {
+	cache.cache.Close()
+}
+
+// Flush removes all data and returns number of flushed entries
+func (cache *MemoryByteCache) Flush() int {
+	return cache.cache.Flush()
+}
+
+// Count returns number of entries of all sets
+func (cache *MemoryByteCache) Count() int {
+	return cache.cache.Count()
+}
+
+// ClearSet removes all data of the set
+func (cache *MemoryByteCache) ClearSet(set string) error {
+	cache.cache.FlushSet(set)
+
+	return nil
+}
+
+// ScanKeys returns keys of not expired data of the set, empty set means all sets
+func (cache *MemoryByteCache) ScanKeys(set string) ([]Key, error) {
+	found, _, err := cache.cache.FindKeys(FindOptions{Set: set})
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]Key, len(found))
+	for i := range found {
+		keys[i] = found[i].Key
+	}
+
+	return keys, nil
+}
+
+// GetCtx returns copy of data by key unless context is done
+func (cache *MemoryByteCache) GetCtx(ctx context.Context, key *Key) ([]byte, bool, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, false, err
+	}
+
+	data, ok := cache.Get(key)
+
+	return data, ok, nil
+}
+
+// PutCtx puts copy of data by key unless context is done
+func (cache *MemoryByteCache) PutCtx(ctx context.Context, data []byte, key *Key, ttl time.Duration) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	return cache.cache.Put(cloneBytes(data), key, ttl)
+}
+
+// RemoveCtx removes data by key unless context is done
+func (cache *MemoryByteCache) RemoveCtx(ctx context.Context, key *Key) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	return cache.Remove(key)
+}
+
+// ClearSetCtx removes all data of the set unless context is done
+func (cache *MemoryByteCache) ClearSetCtx(ctx context.Context, set string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	return cache.ClearSet(set)
+}
+
+// ScanKeysCtx returns keys of the set unless context is done
+func (cache *MemoryByteCache) ScanKeysCtx(ctx context.Context, set string) ([]Key, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	return cache.ScanKeys(set)
+}
+
+// GetMulti returns copies of data by keys. Results are keyed by Key.ID()
+func (cache *MemoryByteCache) GetMulti(keys []*Key) map[string]ByteResult {
+	results := make(map[string]ByteResult, len(keys))
+	for id, result := range cache.cache.GetMulti(keys) {
+		data, found := bytesOf(result.Data, result.Found)
+		results[id] = ByteResult{Data: data, Found: found}
+	}
+
+	return results
+}
+
+// PutMulti puts copies of data by keys. Returns errors of failed keys keyed by Key.ID()
+func (cache *MemoryByteCache) PutMulti(items []ByteItem, ttl time.Duration) map[string]error {
+	structItems := make([]StructItem, len(items))
+	for i := range items {
+		structItems[i] = StructItem{Key: items[i].Key, Data: cloneBytes(items[i].Data)}
+	}
+
+	return cache.cache.PutMulti(structItems, ttl)
+}
+
+// RemoveMulti removes data by keys
+func (cache *MemoryByteCache) RemoveMulti(keys []*Key) map[string]error {
+	cache.cache.RemoveMulti(keys)
+
+	return nil
+}
+
+// PutNegative puts negative entry, the key is known to be absent for ttl
+func (cache *MemoryByteCache) PutNegative(key *Key, ttl time.Duration) error {
+	return cache.cache.PutNegative(key, ttl)
+}
+
+// Lookup returns copy of data by key and tells whether it is hit, miss or known absence
+func (cache *MemoryByteCache) Lookup(key *Key) ([]byte, LookupResult) {
+	data, result := cache.cache.Lookup(key)
+	if result != LookupHit {
+		return nil, result
+	}
+
+	buf, ok := bytesOf(data, true)
+	if !ok {
+		return nil, LookupMiss
+	}
+
+	return buf, result
+}
+
+// TTL returns remaining time to live of data by key
+func (cache *MemoryByteCache) TTL(key *Key) (time.Duration, bool) {
+	return cache.cache.TTL(key)
+}
+
+// GetWithTTLCtx returns copy of data by key with its remaining time to live unless context is done
+func (cache *MemoryByteCache) GetWithTTLCtx(ctx context.Context, key *Key) ([]byte, time.Duration, bool, error) {
+	data, ok, err := cache.GetCtx(ctx, key)
+	if !ok {
+		return nil, 0, false, err
+	}
+
+	ttl, ok := cache.cache.TTL(key)
+	if !ok {
+		return nil, 0, false, nil
+	}
+
+	return data, ttl, true, nil
+}
+
+// Touch extends life of data by key to ttl from now
+func (cache *MemoryByteCache) Touch(key *Key, ttl time.Duration) (bool, error) {
+	return cache.cache.Touch(key, ttl)
+}
+
+// PutUntil puts copy of data which expires at given time
+func (cache *MemoryByteCache) PutUntil(data []byte, key *Key, until time.Time) error {
+	return cache.cache.PutUntil(cloneBytes(data), key, until)
+}
+
+// GetVersioned returns copy of data by key with version of its entry
+func (cache *MemoryByteCache) GetVersioned(key *Key) ([]byte, uint64, bool) {
+	data, version, ok := cache.cache.GetVersioned(key)
+	buf, ok := bytesOf(data, ok)
+
+	return buf, version, ok
+}
+
+// CompareAndSwap puts copy of data only if version of its entry has not changed
+func (cache *MemoryByteCache) CompareAndSwap(key *Key, version uint64, data []byte, ttl time.Duration) error {
+	return cache.cache.CompareAndSwap(key, version, cloneBytes(data), ttl)
+}
+
+// Incr atomically adds delta to counter by key and returns new value. Missing counter starts at zero.
+// Like counter bin of Aerospike record, counter is kept apart from data of the key: Get doesn't return it,
+// Incr keeps data and its tags unless key has tags, Put replaces both. ttl counts from the last change of the key
+func (cache *MemoryByteCache) Incr(key *Key, delta int64, ttl time.Duration) (int64, error) {
+	for {
+		view, _ := cache.cache.get(key)
+
+		record, _ := view.data.(memoryRecord)
+		if buf, ok := view.data.([]byte); ok {
+			record = memoryRecord{data: buf, hasData: true}
+		}
+		record.counter += delta
+
+		storeKey := key
+		if len(key.Tags) == 0 && len(view.key.Tags) > 0 {
+			storeKey = &Key{Set: key.Set, Pk: key.Pk, Tags: view.key.Tags}
+		}
+
+		err := cache.cache.CompareAndSwap(storeKey, view.version, record, ttl)
+		if err != ErrVersionConflict {
+			return record.counter, err
+		}
+	}
+}
+
+// Decr atomically subtracts delta from counter by key and returns new value
+func (cache *MemoryByteCache) Decr(key *Key, delta int64, ttl time.Duration) (int64, error) {
+	return cache.Incr(key, -delta, ttl)
+}
+
+// memoryRecord keeps data and counter of the key, like bins of Aerospike record. Keys without counter keep []byte
+type memoryRecord struct {
+	data    []byte
+	hasData bool
+	counter int64
+}
+
+// bytesOf returns copy of stored data, keys having only counter are missing
+func bytesOf(data interface{}, ok bool) ([]byte, bool) {
+	if !ok {
+		return nil, false
+	}
+
+	switch value := data.(type) {
+	case []byte:
+		return cloneBytes(value), true
+	case memoryRecord:
+		return cloneBytes(value.data), value.hasData
+	}
+
+	return nil, false
+}
+
+// cloneBytes returns copy of buf, nil stays nil
+func cloneBytes(buf []byte) []byte {
+	if buf == nil {
+		return nil
+	}
+
+	return append(make([]byte, 0, len(buf)), buf...)
+}
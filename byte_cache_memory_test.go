@@ -0,0 +1,183 @@
+package cache
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"go-cache/metric/dummy"
+)
+
+func TestMemoryByteCache_Get(t *testing.T) {
+	clock := NewFakeClock(time.Now())
+	cache := NewMemoryByteCache(100, nil, dummy.NewMetric())
+	cache.SetClock(clock)
+
+	data := []byte("test1")
+	key := &Key{Set: "testset", Pk: "test1"}
+	cache.Put(data, key, time.Minute)
+
+	data[0] = 'T'
+	got, ok := cache.Get(key)
+	if !ok || string(got) != "test1" {
+		t.Fatalf("Stored data should not share memory with put buffer, got %q, %v", got, ok)
+	}
+
+	got[0] = 'T'
+	if got, _ := cache.Get(key); string(got) != "test1" {
+		t.Errorf("Stored data should not share memory with returned buffer, got %q", got)
+	}
+
+	clock.Advance(time.Minute)
+
+	if _, ok := cache.Get(key); ok {
+		t.Error("Expired data should not be returned")
+	}
+	if cache.Count() != 0 {
+		t.Errorf("Expected empty cache, got %d entries", cache.Count())
+	}
+}
+
+func TestMemoryByteCache_RemoveByTag(t *testing.T) {
+	cache := NewMemoryByteCache(100, nil, dummy.NewMetric())
+
+	key1 := &Key{Set: "testset", Pk: "1", Tags: []string{"tag1"}}
+	key2 := &Key{Set: "testset", Pk: "2", Tags: []string{"tag1", "tag2"}}
+	key3 := &Key{Set: "testset", Pk: "3", Tags: []string{"tag2"}}
+	for _, key := range []*Key{key1, key2, key3} {
+		cache.Put([]byte(key.Pk), key, time.Minute)
+	}
+
+	if err := cache.Remove(&Key{Set: "testset", Tags: []string{"tag1"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := cache.Get(key1); ok {
+		t.Error("Data with removed tag should be removed")
+	}
+	if _, ok := cache.Get(key2); ok {
+		t.Error("Data with removed tag should be removed")
+	}
+	if _, ok := cache.Get(key3); !ok {
+		t.Error("Data without removed tag should be kept")
+	}
+}
+
+func TestMemoryByteCache_ScanKeysAndClearSet(t *testing.T) {
+	cache := NewMemoryByteCache(100, nil, dummy.NewMetric())
+
+	cache.Put([]byte("1"), &Key{Set: "set1", Pk: "1", Tags: []string{"tag"}}, time.Minute)
+	cache.Put([]byte("2"), &Key{Set: "set1", Pk: "2"}, time.Minute)
+	cache.Put([]byte("3"), &Key{Set: "set2", Pk: "3"}, time.Minute)
+
+	keys, err := cache.ScanKeys("set1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].Pk < keys[j].Pk })
+	if len(keys) != 2 || keys[0].Pk != "1" || keys[1].Pk != "2" || len(keys[0].Tags) != 1 {
+		t.Errorf("Unexpected keys %v", keys)
+	}
+
+	if err := cache.ClearSet("set1"); err != nil {
+		t.Fatal(err)
+	}
+	if cache.Count() != 1 {
+		t.Errorf("Only data of cleared set should be removed, got %d entries", cache.Count())
+	}
+
+	if flushed := cache.Flush(); flushed != 1 || cache.Count() != 0 {
+		t.Errorf("Expected 1 flushed entry, got %d, %d left", flushed, cache.Count())
+	}
+}
+
+func TestMemoryByteCache_Ctx(t *testing.T) {
+	cache := NewMemoryByteCache(100, nil, dummy.NewMetric())
+	key := &Key{Set: "testset", Pk: "1"}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err := cache.PutCtx(ctx, []byte("1"), key, time.Minute); err != context.Canceled {
+		t.Errorf("Expected canceled put, got %v", err)
+	}
+	if _, ok, err := cache.GetCtx(context.Background(), key); ok || err != nil {
+		t.Errorf("Canceled put should not store data, got %v, %v", ok, err)
+	}
+
+	cache.Put([]byte("1"), key, time.Minute)
+	if data, ttl, ok, err := cache.GetWithTTLCtx(context.Background(), key); !ok || err != nil || string(data) != "1" || ttl <= 0 || ttl > time.Minute {
+		t.Errorf("Unexpected get with TTL result %q, %s, %v, %v", data, ttl, ok, err)
+	}
+	if _, _, ok, err := cache.GetWithTTLCtx(ctx, key); ok || err != context.Canceled {
+		t.Errorf("Expected canceled get, got %v, %v", ok, err)
+	}
+}
+
+func TestMemoryByteCache_Counter(t *testing.T) {
+	cache := NewMemoryByteCache(100, nil, dummy.NewMetric())
+	key := &Key{Set: "counters", Pk: "views"}
+
+	if value, err := cache.Incr(key, 2, time.Minute); err != nil || value != 2 {
+		t.Fatalf("Unexpected counter %d, %v", value, err)
+	}
+	if _, ok := cache.Get(key); ok {
+		t.Error("Counter should not be returned as data")
+	}
+	if _, result := cache.Lookup(key); result != LookupMiss {
+		t.Errorf("Counter should be looked up as miss, got %s", result)
+	}
+
+	cache.Put([]byte("data"), key, time.Minute)
+	if value, err := cache.Incr(key, 3, time.Minute); err != nil || value != 3 {
+		t.Fatalf("Put should replace counter, got %d, %v", value, err)
+	}
+	if value, err := cache.Decr(key, 1, time.Minute); err != nil || value != 2 {
+		t.Errorf("Unexpected counter %d, %v", value, err)
+	}
+	if data, ok := cache.Get(key); !ok || string(data) != "data" {
+		t.Errorf("Incr should keep data of the key, got %q, %v", data, ok)
+	}
+}
+
+func TestMemoryByteCache_Counter_KeepsTags(t *testing.T) {
+	cache := NewMemoryByteCache(100, nil, dummy.NewMetric())
+	key := &Key{Set: "counters", Pk: "views"}
+
+	cache.Put([]byte("data"), &Key{Set: "counters", Pk: "views", Tags: []string{"t"}}, time.Minute)
+	if value, err := cache.Incr(key, 1, time.Minute); err != nil || value != 1 {
+		t.Fatalf("Unexpected counter %d, %v", value, err)
+	}
+
+	if err := cache.Remove(&Key{Set: "counters", Tags: []string{"t"}}); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if data, ok := cache.Get(key); ok {
+		t.Errorf("Data should be removed by tag after Incr, got %q", data)
+	}
+}
+
+func TestMemoryByteCache_Counter_Concurrent(t *testing.T) {
+	cache := NewMemoryByteCache(100, nil, dummy.NewMetric())
+	key := &Key{Set: "counters", Pk: "views"}
+
+	const workers, increments = 8, 100
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < increments; j++ {
+				cache.Incr(key, 1, time.Minute)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if value, _ := cache.Incr(key, 0, time.Minute); value != workers*increments {
+		t.Errorf("Expected %d, got %d", workers*increments, value)
+	}
+}
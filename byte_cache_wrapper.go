@@ -1,6 +1,9 @@
 package cache
 
-import "time"
+import (
+	"context"
+	"time"
+)
 
 const (
 	RetryTimeout = time.Second * 10
@@ -11,6 +14,7 @@ type wrapperCache struct {
 	stubCache   IByteCache
 	isConnected bool
 	doneChan    chan bool
+	clock       Clock
 	logger      IAerospikeCacheLogger
 }
 
@@ -18,8 +22,14 @@ type fnCreate func() (IByteCache, error)
 
 // NewEntryCacheWrapper initializes instance of IEntryCache
 func NewEntryCacheWrapper(fn fnCreate, logger IAerospikeCacheLogger) IByteCache {
+	return NewEntryCacheWrapperWithClock(fn, logger, RealClock)
+}
+
+// NewEntryCacheWrapperWithClock initializes instance of IEntryCache which retries creation of real cache by given clock
+func NewEntryCacheWrapperWithClock(fn fnCreate, logger IAerospikeCacheLogger, clock Clock) IByteCache {
 	result := &wrapperCache{
 		stubCache: NewBlackholeCache(),
+		clock:     clock,
 		logger:    logger,
 		doneChan:  make(chan bool, 1),
 	}
@@ -43,7 +53,7 @@ func (this *wrapperCache) createRealCache(fn fnCreate) {
 		case <-this.doneChan:
 			close(this.doneChan)
 			return
-		case <-time.After(RetryTimeout):
+		case <-this.clock.After(RetryTimeout):
 			if cache, err := fn(); err == nil {
 				this.realCache = cache
 				this.isConnected = true
@@ -94,3 +104,93 @@ func (this *wrapperCache) Count() int {
 func (this *wrapperCache) ClearSet(set string) error {
 	return this.getCache().ClearSet(set)
 }
+
+// GetCtx returns data by key from wrapped cache
+func (this *wrapperCache) GetCtx(ctx context.Context, key *Key) ([]byte, bool, error) {
+	return this.getCache().GetCtx(ctx, key)
+}
+
+// PutCtx puts data into wrapped cache
+func (this *wrapperCache) PutCtx(ctx context.Context, data []byte, key *Key, ttl time.Duration) error {
+	return this.getCache().PutCtx(ctx, data, key, ttl)
+}
+
+// RemoveCtx removes data from wrapped cache
+func (this *wrapperCache) RemoveCtx(ctx context.Context, key *Key) error {
+	return this.getCache().RemoveCtx(ctx, key)
+}
+
+// ClearSetCtx removes all values of set from wrapped cache
+func (this *wrapperCache) ClearSetCtx(ctx context.Context, set string) error {
+	return this.getCache().ClearSetCtx(ctx, set)
+}
+
+// ScanKeysCtx returns all keys of set from wrapped cache
+func (this *wrapperCache) ScanKeysCtx(ctx context.Context, set string) ([]Key, error) {
+	return this.getCache().ScanKeysCtx(ctx, set)
+}
+
+// GetMulti returns data by keys from wrapped cache
+func (this *wrapperCache) GetMulti(keys []*Key) map[string]ByteResult {
+	return this.getCache().GetMulti(keys)
+}
+
+// PutMulti puts data into wrapped cache
+func (this *wrapperCache) PutMulti(items []ByteItem, ttl time.Duration) map[string]error {
+	return this.getCache().PutMulti(items, ttl)
+}
+
+// RemoveMulti removes data by keys from wrapped cache
+func (this *wrapperCache) RemoveMulti(keys []*Key) map[string]error {
+	return this.getCache().RemoveMulti(keys)
+}
+
+// PutNegative puts negative entry into wrapped cache
+func (this *wrapperCache) PutNegative(key *Key, ttl time.Duration) error {
+	return this.getCache().PutNegative(key, ttl)
+}
+
+// Lookup returns data by key from wrapped cache and tells whether it is hit, miss or known absence
+func (this *wrapperCache) Lookup(key *Key) ([]byte, LookupResult) {
+	return this.getCache().Lookup(key)
+}
+
+// TTL returns remaining time to live of data by key from wrapped cache
+func (this *wrapperCache) TTL(key *Key) (time.Duration, bool) {
+	return this.getCache().TTL(key)
+}
+
+// GetWithTTLCtx returns data by key with its remaining time to live from wrapped cache
+func (this *wrapperCache) GetWithTTLCtx(ctx context.Context, key *Key) ([]byte, time.Duration, bool, error) {
+	return this.getCache().GetWithTTLCtx(ctx, key)
+}
+
+// Touch extends life of data by key in wrapped cache
+func (this *wrapperCache) Touch(key *Key, ttl time.Duration) (bool, error) {
+	return this.getCache().Touch(key, ttl)
+}
+
+// PutUntil puts data which expires at given time into wrapped cache
+func (this *wrapperCache) PutUntil(data []byte, key *Key, until time.Time) error {
+	return this.getCache().PutUntil(data, key, until)
+}
+
+// GetVersioned returns data by key with its version from wrapped cache
+func (this *wrapperCache) GetVersioned(key *Key) ([]byte, uint64, bool) {
+	return this.getCache().GetVersioned(key)
+}
+
+// CompareAndSwap puts data into wrapped cache if version of record has not changed
+func (this *wrapperCache) CompareAndSwap(key *Key, version uint64, data []byte, ttl time.Duration) error {
+	return this.getCache().CompareAndSwap(key, version, data, ttl)
+}
+
+// Incr adds delta to counter by key in wrapped cache
+func (this *wrapperCache) Incr(key *Key, delta int64, ttl time.Duration) (int64, error) {
+	return this.getCache().Incr(key, delta, ttl)
+}
+
+// Decr subtracts delta from counter by key in wrapped cache
+func (this *wrapperCache) Decr(key *Key, delta int64, ttl time.Duration) (int64, error) {
+	return this.getCache().Decr(key, delta, ttl)
+}
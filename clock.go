@@ -0,0 +1,175 @@
+package cache
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock is source of time for expiration and background jobs, it allows to control time in tests
+type Clock interface {
+	Now() time.Time
+	NewTicker(d time.Duration) Ticker
+	After(d time.Duration) <-chan time.Time
+}
+
+// Ticker delivers ticks of Clock
+type Ticker interface {
+	C() <-chan time.Time
+	Stop()
+}
+
+// RealClock is Clock based on time package
+var RealClock Clock = realClock{}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+func (realClock) NewTicker(d time.Duration) Ticker {
+	return realTicker{time.NewTicker(d)}
+}
+
+func (realClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+// realTicker adapts time.Ticker to Ticker
+type realTicker struct {
+	ticker *time.Ticker
+}
+
+func (t realTicker) C() <-chan time.Time {
+	return t.ticker.C
+}
+
+func (t realTicker) Stop() {
+	t.ticker.Stop()
+}
+
+// FakeClock is Clock which moves only by Advance. Tickers and timers fire during Advance,
+// like time.Ticker slow receivers skip ticks
+type FakeClock struct {
+	lock    sync.Mutex
+	now     time.Time
+	tickers []*fakeTicker
+	timers  []*fakeTimer
+}
+
+// NewFakeClock returns new instance of FakeClock showing given time
+func NewFakeClock(now time.Time) *FakeClock {
+	return &FakeClock{now: now}
+}
+
+// Now implements Clock
+func (clock *FakeClock) Now() time.Time {
+	clock.lock.Lock()
+	defer clock.lock.Unlock()
+
+	return clock.now
+}
+
+// NewTicker implements Clock
+func (clock *FakeClock) NewTicker(d time.Duration) Ticker {
+	if d <= 0 {
+		panic("non-positive interval for FakeClock.NewTicker")
+	}
+
+	clock.lock.Lock()
+	defer clock.lock.Unlock()
+
+	ticker := &fakeTicker{
+		clock:    clock,
+		c:        make(chan time.Time, 1),
+		interval: d,
+		next:     clock.now.Add(d),
+	}
+	clock.tickers = append(clock.tickers, ticker)
+
+	return ticker
+}
+
+// After implements Clock
+func (clock *FakeClock) After(d time.Duration) <-chan time.Time {
+	clock.lock.Lock()
+	defer clock.lock.Unlock()
+
+	timer := &fakeTimer{
+		c:  make(chan time.Time, 1),
+		at: clock.now.Add(d),
+	}
+	if d <= 0 {
+		timer.c <- clock.now
+		return timer.c
+	}
+	clock.timers = append(clock.timers, timer)
+
+	return timer.c
+}
+
+// Advance moves clock forward and fires tickers and timers which are due
+func (clock *FakeClock) Advance(d time.Duration) {
+	clock.lock.Lock()
+	defer clock.lock.Unlock()
+
+	clock.now = clock.now.Add(d)
+
+	for _, ticker := range clock.tickers {
+		if ticker.next.After(clock.now) {
+			continue
+		}
+
+		select {
+		case ticker.c <- clock.now:
+		default:
+		}
+		for !ticker.next.After(clock.now) {
+			ticker.next = ticker.next.Add(ticker.interval)
+		}
+	}
+
+	timers := clock.timers[:0]
+	for _, timer := range clock.timers {
+		if timer.at.After(clock.now) {
+			timers = append(timers, timer)
+			continue
+		}
+		timer.c <- clock.now
+	}
+	clock.timers = timers
+}
+
+func (clock *FakeClock) removeTicker(ticker *fakeTicker) {
+	clock.lock.Lock()
+	defer clock.lock.Unlock()
+
+	for i, t := range clock.tickers {
+		if t == ticker {
+			clock.tickers = append(clock.tickers[:i], clock.tickers[i+1:]...)
+			return
+		}
+	}
+}
+
+// fakeTicker is Ticker of FakeClock
+type fakeTicker struct {
+	clock    *FakeClock
+	c        chan time.Time
+	interval time.Duration
+	next     time.Time
+}
+
+func (t *fakeTicker) C() <-chan time.Time {
+	return t.c
+}
+
+func (t *fakeTicker) Stop() {
+	t.clock.removeTicker(t)
+}
+
+// fakeTimer is pending channel returned by FakeClock.After
+type fakeTimer struct {
+	c  chan time.Time
+	at time.Time
+}
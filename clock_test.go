@@ -0,0 +1,123 @@
+package cache
+
+import (
+	"testing"
+	"time"
+
+	"go-cache/metric/dummy"
+)
+
+func TestFakeClock_Advance(t *testing.T) {
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+
+	ticker := clock.NewTicker(time.Second)
+	after := clock.After(3 * time.Second)
+
+	clock.Advance(500 * time.Millisecond)
+	select {
+	case <-ticker.C():
+		t.Fatal("Ticker fired too early")
+	default:
+	}
+
+	clock.Advance(2 * time.Second)
+	if now := clock.Now(); !now.Equal(start.Add(2500 * time.Millisecond)) {
+		t.Errorf("Unexpected time %s", now)
+	}
+	select {
+	case <-ticker.C():
+	default:
+		t.Fatal("Ticker should fire")
+	}
+	select {
+	case <-after:
+		t.Fatal("Timer fired too early")
+	default:
+	}
+
+	clock.Advance(time.Second)
+	select {
+	case <-after:
+	default:
+		t.Fatal("Timer should fire")
+	}
+
+	ticker.Stop()
+	<-ticker.C()
+	clock.Advance(time.Minute)
+	select {
+	case <-ticker.C():
+		t.Fatal("Stopped ticker fired")
+	default:
+	}
+}
+
+func TestStructCache_FakeClock_SoftTTL(t *testing.T) {
+	clock := NewFakeClock(time.Now())
+	structCache := NewStructCacheObject(10, nil, dummy.NewMetric())
+	structCache.SetClock(clock)
+
+	k := &Key{Set: "set1", Pk: "1"}
+	if err := structCache.PutWithSoftTTL("data", k, time.Second, time.Minute); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, stale, ok := structCache.GetWithStale(k); !ok || stale {
+		t.Errorf("Expected fresh entry, found: %t, stale: %t", ok, stale)
+	}
+
+	clock.Advance(time.Second)
+	if _, stale, ok := structCache.GetWithStale(k); !ok || !stale {
+		t.Errorf("Expected stale entry, found: %t, stale: %t", ok, stale)
+	}
+
+	clock.Advance(time.Minute)
+	if _, ok := structCache.Get(k); ok {
+		t.Error("Entry should be expired")
+	}
+}
+
+func TestStructCache_FakeClock_Collector(t *testing.T) {
+	clock := NewFakeClock(time.Now())
+	structCache := NewStructCacheObject(10, nil, dummy.NewMetric())
+	structCache.SetClock(clock)
+	structCache.RegisterCacheSetWithOptions("set1", CacheSetOptions{Limit: 10, CollectorInterval: time.Minute})
+	defer structCache.Close()
+
+	structCache.Put("data", &Key{Set: "set1", Pk: "1"}, time.Second)
+	clock.Advance(time.Minute)
+
+	for i := 0; i < 100 && structCache.Count() != 0; i++ {
+		time.Sleep(time.Millisecond)
+	}
+	if count := structCache.Count(); count != 0 {
+		t.Errorf("Expired entry should be collected, count %d", count)
+	}
+}
+
+func TestEntryAutoCache_FakeClock(t *testing.T) {
+	clock := NewFakeClock(time.Now())
+	updates := make(chan int, 10)
+	calls := 0
+
+	entry := CreateEntryAutoCache(func() (interface{}, error) {
+		calls++
+		updates <- calls
+		return calls, nil
+	}, time.Hour, "entry", NewNilLogger())
+	entry.SetClock(clock)
+
+	if err := entry.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer entry.Stop()
+	<-updates
+
+	clock.Advance(time.Hour)
+	select {
+	case <-updates:
+	case <-time.After(time.Second):
+		t.Fatal("Updater should be called by clock tick")
+	}
+}
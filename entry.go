@@ -8,21 +8,100 @@ import (
 type Entry struct {
 	Key        *Key
 	CreateDate time.Time // In UTC
-	EndDate    int64
+	EndDate    int64     // In Unix seconds, Expires is used instead if it is set
 	Data       interface{}
+
+	// Expires is precise end of entry life. Entries created in process keep monotonic clock reading,
+	// so expiration doesn't depend on wall clock changes
+	Expires time.Time
+
+	// SoftExpires is end of freshness of entry, between SoftExpires and Expires entry is stale.
+	// Zero means entry has no soft TTL
+	SoftExpires time.Time
+
+	// position of entry in eviction order of cache set shard
+	evictorNode interface{}
+	// position of entry in expiry index of cache set shard
+	expiryIndex int
+	// approximate size of Data in bytes, measured only for byte limited sets
+	size int64
+	// TTLs entry was put with, used to put refreshed value
+	softTTL time.Duration
+	ttl     time.Duration
+	// version changes on every put of the entry, see CompareAndSwap
+	version uint64
 }
 
 // CreateEntry returns new instance of Entry
 func CreateEntry(key *Key, endDate int64, data interface{}) *Entry {
+	return createEntryAt(time.Now(), key, endDate, data)
+}
+
+func createEntryAt(now time.Time, key *Key, endDate int64, data interface{}) *Entry {
 	return &Entry{
 		Key:        key,
-		CreateDate: time.Now().UTC(),
+		CreateDate: now.UTC(),
 		EndDate:    endDate,
 		Data:       data,
 	}
 }
 
+// CreateEntryWithTTL returns new instance of Entry which expires after ttl with nanosecond precision
+func CreateEntryWithTTL(key *Key, ttl time.Duration, data interface{}) *Entry {
+	now := time.Now()
+	entry := createEntryAt(now, key, 0, data)
+	entry.setTTL(now, 0, ttl)
+
+	return entry
+}
+
 // IsValid returns Entry is valid
 func (entry *Entry) IsValid() bool {
-	return entry.EndDate > time.Now().Unix()
+	return entry.isValidAt(time.Now())
+}
+
+func (entry *Entry) isValidAt(now time.Time) bool {
+	if !entry.Expires.IsZero() {
+		return now.Before(entry.Expires)
+	}
+
+	return entry.EndDate > now.Unix()
+}
+
+// expiresAt returns end of entry life
+func (entry *Entry) expiresAt() time.Time {
+	if !entry.Expires.IsZero() {
+		return entry.Expires
+	}
+
+	return time.Unix(entry.EndDate, 0)
+}
+
+// IsStale returns Entry has outlived its soft TTL
+func (entry *Entry) IsStale() bool {
+	return entry.isStaleAt(time.Now())
+}
+
+func (entry *Entry) isStaleAt(now time.Time) bool {
+	return !entry.SoftExpires.IsZero() && !now.Before(entry.SoftExpires)
+}
+
+// setTTL sets expiration of entry counting from now, zero softTTL means entry has no soft TTL
+func (entry *Entry) setTTL(now time.Time, softTTL, ttl time.Duration) {
+	entry.softTTL = softTTL
+	entry.ttl = ttl
+
+	entry.Expires = now.Add(ttl)
+	entry.EndDate = entry.Expires.Unix()
+
+	entry.SoftExpires = time.Time{}
+	if softTTL > 0 {
+		entry.SoftExpires = now.Add(softTTL)
+	}
+}
+
+// setExpires changes end of entry life keeping its soft expiration and TTLs of refresh
+func (entry *Entry) setExpires(expires time.Time) {
+	entry.Expires = expires
+	entry.EndDate = expires.Unix()
 }
@@ -34,3 +34,19 @@ func (key Key) ID() string {
 
 	return buf.String()
 }
+
+// batchSet returns set of all keys of batch or "*" if keys belong to different sets
+func batchSet(keys []*Key) string {
+	if len(keys) == 0 {
+		return ""
+	}
+
+	set := keys[0].Set
+	for _, key := range keys[1:] {
+		if key.Set != set {
+			return "*"
+		}
+	}
+
+	return set
+}
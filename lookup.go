@@ -0,0 +1,35 @@
+package cache
+
+import "go-cache/errors"
+
+// ErrNotFound means key is known to be absent, loaders may return it to cache absence of value
+var ErrNotFound = errors.New("Key is known to be absent")
+
+// LookupResult tells whether cache has value for the key
+type LookupResult int
+
+const (
+	// LookupMiss cache knows nothing about the key
+	LookupMiss LookupResult = iota
+	// LookupHit cache has value for the key
+	LookupHit
+	// LookupAbsent key is known to be absent (negative entry)
+	LookupAbsent
+)
+
+// String implements fmt.Stringer interface
+func (result LookupResult) String() string {
+	switch result {
+	case LookupMiss:
+		return "miss"
+	case LookupHit:
+		return "hit"
+	case LookupAbsent:
+		return "absent"
+	}
+
+	return "unknown"
+}
+
+// ErrVersionConflict means entry was changed since its version was read, see CompareAndSwap
+var ErrVersionConflict = errors.New("Version of entry has changed")
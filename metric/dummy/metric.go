@@ -18,6 +18,10 @@ func (m Metric) RegisterMiss(labels map[string]string) {
 	return
 }
 
+func (m Metric) RegisterNegativeHit(labels map[string]string) {
+	return
+}
+
 func (m Metric) IncreaseItemCount(set string) {
 	return
 }
@@ -25,3 +29,11 @@ func (m Metric) IncreaseItemCount(set string) {
 func (m Metric) SetItemCount(set string, n int) {
 	return
 }
+
+func (m Metric) SetByteCount(set string, n int) {
+	return
+}
+
+func (m Metric) AddCollectedCount(set string, n int) {
+	return
+}
@@ -8,14 +8,18 @@ const (
 	LabelNamespace = "namespace"
 	LabelSet       = "set"
 	LabelOperation = "operation"
+	LabelTier      = "tier"
 )
 
 type Metric interface {
 	ObserveRT(labels map[string]string, timeSince float64)
 	RegisterHit(labels map[string]string)
 	RegisterMiss(labels map[string]string)
+	RegisterNegativeHit(labels map[string]string)
 	IncreaseItemCount(set string)
 	SetItemCount(set string, n int)
+	SetByteCount(set string, n int)
+	AddCollectedCount(set string, n int)
 }
 
 // SinceMs just wraps time.Since() with converting result to milliseconds.
@@ -1,32 +1,54 @@
 package cache
 
 import (
+	"context"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
-	"container/list"
-
 	"go-cache/errors"
 	"go-cache/metric"
 )
 
 var ErrSetAlreadyExists = errors.New("Set already exists")
 
+// ErrSetNotFound is returned for operations with set which is not registered
+var ErrSetNotFound = errors.New("Set not found")
+
 type cacheSet struct {
-	elements  map[string]*list.Element
-	lruList   *list.List
-	keysLock  sync.RWMutex
-	keysLimit int
-	name      string
+	shards     []*cacheShard
+	count      int64
+	bytes      int64
+	bytesLimit int64
+	name       string
+
+	// last version given to entry of the set, versions are not reused after entry removal
+	versions uint64
 
 	logger IStructCacheLogger
 
-	ticker *time.Ticker
-	metric metric.Metric
+	defaultTTL   time.Duration
+	maxTTL       time.Duration
+	metricLabels map[string]string
+	valueSafety  ValueSafety
+
+	clock     Clock
+	ticker    Ticker
+	ownTicker bool
+	metric    metric.Metric
+
+	// interval of own ticker of collector, zero if collector runs by given ticker or there is no collector
+	collectorInterval time.Duration
+
+	// callbacks of the set and of the whole cache
+	onEvict      *evictCallbacks
+	cacheOnEvict *evictCallbacks
 
 	// connection count metric
 	quitCollectorChan chan struct{}
+	// closed when collector has stopped
+	collectorDone chan struct{}
 }
 
 // StructCache is simple storage with locking
@@ -34,11 +56,28 @@ type StructCache struct {
 	setsCollection map[string]*cacheSet
 	setsLock       sync.RWMutex
 	defaultLimit   int
+	// SetLimit changes limit of registered sets too
+	limitAppliesToSets bool
 
 	logger IStructCacheLogger
 
-	ticker *time.Ticker
-	metric metric.Metric
+	// options of sets created implicitly by Put, limit is taken from defaultLimit
+	defaultOptions CacheSetOptions
+	clock          Clock
+	metric         metric.Metric
+
+	// in-flight loader calls by Key.ID()
+	loads     map[string]*loadCall
+	loadsLock sync.Mutex
+
+	// loaders refreshing stale entries by set name
+	loaders map[string]func(key *Key) (interface{}, error)
+
+	onEvict *evictCallbacks
+
+	// snapshot file written on Close, empty if disabled
+	snapshotPath  string
+	snapshotCodec SnapshotCodec
 }
 
 var _ IStructCache = &StructCache{} // StructCache implements IStructCache
@@ -51,10 +90,15 @@ func NewStructCacheObject(limit int, logger IStructCacheLogger, metric metric.Me
 
 	cache := &StructCache{
 		defaultLimit:   limit,
-		ticker:         time.NewTicker(5 * time.Minute), // @todo make it changeable param
+		defaultOptions: CacheSetOptions{CollectorInterval: DefaultCollectorInterval},
+		clock:          RealClock,
 		setsCollection: make(map[string]*cacheSet),
 		logger:         logger,
 		metric:         metric,
+		loads:          make(map[string]*loadCall),
+		loaders:        make(map[string]func(key *Key) (interface{}, error)),
+		onEvict:        &evictCallbacks{},
+		snapshotCodec:  GobSnapshotCodec{},
 	}
 
 	if cache.logger.IsDebugEnabled() {
@@ -64,60 +108,79 @@ func NewStructCacheObject(limit int, logger IStructCacheLogger, metric metric.Me
 	return cache
 }
 
+// SetLimit changes limit of sets created later, and of all registered sets if ApplyLimitToSets is enabled
 func (cache *StructCache) SetLimit(limit int) {
-	if cache.defaultLimit == limit {
-		return
-	}
+	cache.setsLock.Lock()
+	defer cache.setsLock.Unlock()
 
-	if cache.logger.IsDebugEnabled() {
+	if cache.defaultLimit != limit && cache.logger.IsDebugEnabled() {
 		cache.logger.Debugf("struct_cache: limit has changed, new value: %d", limit)
 	}
 
-	cache.setsLock.Lock()
 	cache.defaultLimit = limit
-	cache.setsLock.Unlock()
+
+	if cache.limitAppliesToSets && limit > 0 {
+		for _, set := range cache.setsCollection {
+			set.setLimit(limit)
+		}
+	}
 }
 
-func (set *cacheSet) getKeyFromSet(key *Key) (interface{}, time.Time, bool) {
+func (set *cacheSet) shard(pk string) *cacheShard {
+	return set.shards[shardIndex(pk, len(set.shards))]
+}
 
-	var (
-		created time.Time
-		data    interface{}
-	)
+func (set *cacheSet) len() int {
+	return int(atomic.LoadInt64(&set.count))
+}
 
-	set.keysLock.RLock()
-	el, ok := set.elements[key.Pk]
-	if !ok {
-		set.keysLock.RUnlock()
-		return data, created, ok
-	}
+// entryView is copy of entry fields taken under shard lock
+type entryView struct {
+	// key is stored key of entry with its tags
+	key     Key
+	data    interface{}
+	created time.Time
+	stale   bool
+	softTTL time.Duration
+	ttl     time.Duration
+	version uint64
+	// err is error kept by negative entry
+	err error
+}
 
-	entry, eok := el.Value.(*Entry)
-	set.keysLock.RUnlock()
+func (set *cacheSet) getKeyFromSet(key *Key) (entryView, bool) {
+	var view entryView
 
-	if !eok {
-		return data, created, false
+	shard := set.shard(key.Pk)
+
+	shard.keysLock.Lock()
+	entry, ok := shard.elements[key.Pk]
+	if !ok {
+		shard.keysLock.Unlock()
+		return view, ok
 	}
 
-	set.keysLock.RLock()
-	created = entry.CreateDate
-	valid := entry.IsValid()
-	set.keysLock.RUnlock()
+	view.created = entry.CreateDate
 
-	if !valid {
-		set.remove(key)
-		return data, created, false
-	}
+	now := set.clock.Now()
+	if !entry.isValidAt(now) {
+		shard.removeEntry(entry)
+		shard.keysLock.Unlock()
 
-	set.keysLock.RLock()
-	data = entry.Data
-	set.keysLock.RUnlock()
+		set.evicted([]*Entry{entry}, EvictExpired)
+		return view, false
+	}
 
-	set.keysLock.Lock()
-	set.lruList.MoveToFront(el)
-	set.keysLock.Unlock()
+	view.key = *entry.Key
+	view.data = entry.Data
+	view.stale = entry.isStaleAt(now)
+	view.softTTL = entry.softTTL
+	view.ttl = entry.ttl
+	view.version = entry.version
+	shard.evictor.Access(entry)
+	shard.keysLock.Unlock()
 
-	return data, created, true
+	return view, true
 
 }
 
@@ -131,10 +194,24 @@ func (cache *StructCache) getCacheSet(key *Key) (*cacheSet, bool) {
 
 // GetWithTime returns value and create time(UTC) by key
 func (cache *StructCache) GetWithTime(key *Key) (interface{}, time.Time, bool) {
+	view, result := cache.get(key)
+
+	return view.data, view.created, result == LookupHit
+}
+
+// GetWithStale returns value by key and flag that value has outlived its soft TTL.
+// Stale value triggers background refresh by loader registered for the set
+func (cache *StructCache) GetWithStale(key *Key) (interface{}, bool, bool) {
+	view, result := cache.get(key)
+
+	return view.data, view.stale, result == LookupHit
+}
+
+func (cache *StructCache) get(key *Key) (entryView, LookupResult) {
 	var (
-		created time.Time
-		data    interface{}
-		ok      bool
+		view   entryView
+		ok     bool
+		result = LookupMiss
 
 		ts = time.Now()
 	)
@@ -142,31 +219,49 @@ func (cache *StructCache) GetWithTime(key *Key) (interface{}, time.Time, bool) {
 	set, setFound := cache.getCacheSet(key)
 
 	if setFound {
-		data, created, ok = set.getKeyFromSet(key)
+		view, ok = set.getKeyFromSet(key)
+	}
+	if ok {
+		if negative, isNegative := view.data.(negativeValue); isNegative {
+			result = LookupAbsent
+			view.data, view.err = nil, negative.err
+		} else {
+			result = LookupHit
+			view.data = set.valueSafety.onGet(view.data)
+		}
 	}
 
-	cache.updateHitOrMissCount(ok, key)
+	cache.updateHitOrMissCount(result, key, set.labels(map[string]string{metric.LabelSet: key.Set}))
 
-	cache.metric.ObserveRT(map[string]string{
+	if result == LookupHit && view.stale {
+		cache.refresh(view.key, view.softTTL, view.ttl)
+	}
+
+	cache.metric.ObserveRT(set.labels(map[string]string{
 		metric.LabelSet:       key.Set,
 		metric.LabelOperation: "get",
-	}, metric.SinceMs(ts))
+	}), metric.SinceMs(ts))
 
-	return data, created, ok
+	return view, result
 }
 
-func (cache *StructCache) updateHitOrMissCount(condition bool, key *Key) {
-	switch condition {
-	case true:
-		cache.metric.RegisterHit(map[string]string{metric.LabelSet: key.Set})
+func (cache *StructCache) updateHitOrMissCount(result LookupResult, key *Key, labels map[string]string) {
+	switch result {
+	case LookupHit:
+		cache.metric.RegisterHit(labels)
 		if cache.logger.IsDebugEnabled() {
 			cache.logger.Debugf("struct_cache: HIT %v", key)
 		}
-	case false:
-		cache.metric.RegisterMiss(map[string]string{metric.LabelSet: key.Set})
+	case LookupMiss:
+		cache.metric.RegisterMiss(labels)
 		if cache.logger.IsDebugEnabled() {
 			cache.logger.Debugf("struct_cache: MISS %v", key)
 		}
+	case LookupAbsent:
+		cache.metric.RegisterNegativeHit(labels)
+		if cache.logger.IsDebugEnabled() {
+			cache.logger.Debugf("struct_cache: NEGATIVE HIT %v", key)
+		}
 	}
 }
 
@@ -177,15 +272,24 @@ func (cache *StructCache) Get(key *Key) (interface{}, bool) {
 	return data, ok
 }
 
+// GetCtx returns value by key unless context is done
+func (cache *StructCache) GetCtx(ctx context.Context, key *Key) (interface{}, bool, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, false, err
+	}
+
+	data, ok := cache.Get(key)
+
+	return data, ok, nil
+}
+
 // Count elements from cache
 func (cache *StructCache) Count() int {
 	var count int
 
 	cache.setsLock.RLock()
 	for _, set := range cache.setsCollection {
-		set.keysLock.RLock()
-		count += set.lruList.Len()
-		set.keysLock.RUnlock()
+		count += set.len()
 	}
 	cache.setsLock.RUnlock()
 
@@ -206,8 +310,21 @@ func (cache *StructCache) Find(maskedKey string, limit int) []string {
 
 	cache.setsLock.RLock()
 	for _, set := range cache.setsCollection {
-		set.keysLock.RLock()
-		for key := range set.elements {
+		result = set.find(result, maskedKey, limit-len(result))
+
+		if len(result) >= limit {
+			break
+		}
+	}
+	cache.setsLock.RUnlock()
+
+	return result
+}
+
+func (set *cacheSet) find(result []string, maskedKey string, limit int) []string {
+	for _, shard := range set.shards {
+		shard.keysLock.RLock()
+		for key := range shard.elements {
 			if strings.Contains(strings.ToLower(key), maskedKey) {
 				result = append(result, key)
 				limit--
@@ -217,18 +334,51 @@ func (cache *StructCache) Find(maskedKey string, limit int) []string {
 				break
 			}
 		}
-		set.keysLock.RUnlock()
+		shard.keysLock.RUnlock()
 
 		if limit <= 0 {
 			break
 		}
 	}
-	cache.setsLock.RUnlock()
 
 	return result
 }
 
 func (cache *StructCache) RegisterCacheSet(setName string, limit int, ticker *time.Ticker) error {
+	return cache.registerCacheSet(setName, CacheSetOptions{Limit: limit}, ticker)
+}
+
+// RegisterShardedCacheSet registers cache set split into independently locked LRU shards.
+// Keys are spread across shards by hash of Key.Pk, set limit is spread across shards
+func (cache *StructCache) RegisterShardedCacheSet(setName string, limit int, shards int, ticker *time.Ticker) error {
+	return cache.registerCacheSet(setName, CacheSetOptions{Limit: limit, Shards: shards}, ticker)
+}
+
+// RegisterCacheSetWithPolicy registers cache set which evicts entries by given eviction policy
+func (cache *StructCache) RegisterCacheSetWithPolicy(setName string, limit int, policy EvictionPolicy, ticker *time.Ticker) error {
+	return cache.registerCacheSet(setName, CacheSetOptions{Limit: limit, EvictionPolicy: policy}, ticker)
+}
+
+// RegisterCacheSetWithByteLimit registers cache set limited by entries count and by approximate size of values in bytes.
+// Size is taken from Sizer interface if value implements it, otherwise it is estimated by reflection
+func (cache *StructCache) RegisterCacheSetWithByteLimit(setName string, limit int, bytesLimit int64, ticker *time.Ticker) error {
+	return cache.registerCacheSet(setName, CacheSetOptions{Limit: limit, BytesLimit: bytesLimit}, ticker)
+}
+
+// RegisterCacheSetWithOptions registers cache set with given options
+func (cache *StructCache) RegisterCacheSetWithOptions(setName string, options CacheSetOptions) error {
+	return cache.registerCacheSet(setName, options, nil)
+}
+
+// registerCacheSet registers cache set, collector is driven by given ticker or by own ticker with options.CollectorInterval
+func (cache *StructCache) registerCacheSet(setName string, options CacheSetOptions, ticker *time.Ticker) error {
+	if options.EvictionPolicy == nil {
+		options.EvictionPolicy = EvictionLRU
+	}
+	if options.BytesLimit < 0 {
+		options.BytesLimit = 0
+	}
+
 	cache.setsLock.Lock()
 	defer cache.setsLock.Unlock()
 
@@ -236,31 +386,93 @@ func (cache *StructCache) RegisterCacheSet(setName string, limit int, ticker *ti
 		return ErrSetAlreadyExists
 	}
 
-	cache.setsCollection[setName] = &cacheSet{
-		elements:  make(map[string]*list.Element),
-		lruList:   list.New(),
-		keysLock:  sync.RWMutex{},
-		keysLimit: limit,
-		name:      setName,
+	set := &cacheSet{
+		bytesLimit: options.BytesLimit,
+		name:       setName,
+
+		defaultTTL:   options.DefaultTTL,
+		maxTTL:       options.MaxTTL,
+		metricLabels: options.MetricLabels,
+		valueSafety:  options.ValueSafety,
 
-		ticker: ticker,
+		clock: cache.clock,
 
 		logger: cache.logger,
 		metric: cache.metric,
 
+		onEvict:      &evictCallbacks{},
+		cacheOnEvict: cache.onEvict,
+
 		quitCollectorChan: make(chan struct{}, 1),
+		collectorDone:     make(chan struct{}),
 	}
 
 	if ticker != nil {
-		go cache.setsCollection[setName].collector()
+		set.ticker = realTicker{ticker}
+	} else if options.CollectorInterval > 0 {
+		set.ticker = cache.clock.NewTicker(options.CollectorInterval)
+		set.ownTicker = true
+		set.collectorInterval = options.CollectorInterval
+	}
+
+	limits := shardLimits(options.Limit, options.Shards)
+	for _, shardLimit := range limits {
+		set.shards = append(set.shards, newCacheShard(shardLimit, shardBytesLimit(options.BytesLimit, len(limits)), options.EvictionPolicy))
+	}
+
+	cache.setsCollection[setName] = set
+
+	if set.ticker != nil {
+		go set.collector()
 	}
 
 	return nil
 }
 
+// SetClock changes source of time of the cache, sets registered before keep previous clock
+func (cache *StructCache) SetClock(clock Clock) {
+	cache.setsLock.Lock()
+	cache.clock = clock
+	cache.setsLock.Unlock()
+}
+
 // Put puts elements into storage
 func (cache *StructCache) Put(data interface{}, key *Key, ttl time.Duration) error {
-	if ttl <= 0 || cache.defaultLimit <= 0 {
+	return cache.put(data, key, 0, ttl)
+}
+
+// PutCtx puts element into storage unless context is done
+func (cache *StructCache) PutCtx(ctx context.Context, data interface{}, key *Key, ttl time.Duration) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	return cache.Put(data, key, ttl)
+}
+
+// PutWithSoftTTL puts element which is fresh for softTTL and is kept up to ttl.
+// Between the two Get returns stale value at once and triggers background refresh by loader registered for the set
+func (cache *StructCache) PutWithSoftTTL(data interface{}, key *Key, softTTL, ttl time.Duration) error {
+	if softTTL <= 0 || softTTL >= ttl {
+		return errors.Errorf("Cannot put element (soft ttl %s should be positive and less than ttl %s)", softTTL, ttl)
+	}
+
+	return cache.put(data, key, softTTL, ttl)
+}
+
+func (cache *StructCache) put(data interface{}, key *Key, softTTL, ttl time.Duration) error {
+	return cache.putIf(data, key, softTTL, ttl, nil)
+}
+
+// putIf puts element if expect accepts version of current entry, nil expect accepts any version
+func (cache *StructCache) putIf(data interface{}, key *Key, softTTL, ttl time.Duration, expect func(version uint64) bool) error {
+	set, err := cache.setForPut(key)
+	if err != nil {
+		return err
+	}
+
+	softTTL, ttl = set.resolveTTL(softTTL, ttl)
+	if ttl <= 0 {
 		return errors.New("Cannot put element (ttl or cache limit is not assign)")
 	}
 
@@ -268,164 +480,324 @@ func (cache *StructCache) Put(data interface{}, key *Key, ttl time.Duration) err
 		cache.logger.Debugf("struct_cache: PUT %q with TTL: %s", key, ttl)
 	}
 
+	return set.putIf(data, key, softTTL, ttl, expect)
+}
+
+// setForPut returns set of the key, set is created with default options if it is not registered yet
+func (cache *StructCache) setForPut(key *Key) (*cacheSet, error) {
+	if cache.defaultLimit <= 0 {
+		return nil, errors.New("Cannot put element (ttl or cache limit is not assign)")
+	}
+
 	set, exists := cache.getCacheSet(key)
 	if !exists {
-		cache.RegisterCacheSet(key.Set, cache.defaultLimit, cache.ticker)
+		cache.registerCacheSet(key.Set, cache.implicitSetOptions(), nil)
 
 		set, exists = cache.getCacheSet(key)
 		if !exists {
-			return errors.Errorf("Cant create set %q", key.Set)
+			return nil, errors.Errorf("Cant create set %q", key.Set)
 		}
 	}
 
-	return set.put(data, key, ttl)
+	return set, nil
 }
 
-func (set *cacheSet) put(data interface{}, key *Key, ttl time.Duration) error {
-	set.keysLock.Lock()
-	defer set.keysLock.Unlock()
+func (set *cacheSet) put(data interface{}, key *Key, softTTL, ttl time.Duration) error {
+	return set.putIf(data, key, softTTL, ttl, nil)
+}
 
+// putIf puts element if expect accepts version of current entry, version of missing or expired entry is 0
+func (set *cacheSet) putIf(data interface{}, key *Key, softTTL, ttl time.Duration, expect func(version uint64) bool) error {
 	ts := time.Now()
 
-	entitiesCount := set.lruList.Len()
-
-	if entitiesCount >= set.keysLimit {
-		if set.logger.IsDebugEnabled() {
-			set.logger.Debug("struct_cache: ATTENTION! Entities count exceeds limit")
+	if _, isNegative := data.(negativeValue); !isNegative {
+		var err error
+		if data, err = set.valueSafety.onPut(data); err != nil {
+			return err
 		}
-		set.trim()
 	}
 
-	if el, ok := set.elements[key.Pk]; ok {
-		set.lruList.MoveToFront(el)
-		if entry, eok := el.Value.(*Entry); eok {
-			entry.EndDate = time.Now().Unix() + int64(ttl.Seconds())
+	shard := set.shard(key.Pk)
 
-			entry.Data = data
-			return nil
-		}
+	size, err := set.measure(shard, data)
+	if err != nil {
+		return err
 	}
 
-	entry := CreateEntry(key, time.Now().Unix()+int64(ttl.Seconds()), data)
-	el := set.lruList.PushFront(entry)
-	set.elements[key.Pk] = el
+	now := set.clock.Now()
 
-	set.metric.ObserveRT(map[string]string{
-		metric.LabelSet:       key.Set,
-		metric.LabelOperation: "put",
-	}, metric.SinceMs(ts))
-	set.metric.IncreaseItemCount(set.name)
+	shard.keysLock.Lock()
+
+	entry, ok := shard.elements[key.Pk]
+
+	if expect != nil {
+		var version uint64
+		if ok && entry.isValidAt(now) {
+			version = entry.version
+		}
+		if !expect(version) {
+			shard.keysLock.Unlock()
+			return ErrVersionConflict
+		}
+	}
+
+	if set.store(shard, entry, key, data, size, softTTL, ttl, now) {
+		set.metric.ObserveRT(set.labels(map[string]string{
+			metric.LabelSet:       key.Set,
+			metric.LabelOperation: "put",
+		}), metric.SinceMs(ts))
+	}
 
 	return nil
 }
 
+// store replaces data of entry or inserts new entry if it is nil. Shard lock must be held by caller, it is released.
+// Returns true if new entry was inserted
+func (set *cacheSet) store(shard *cacheShard, entry *Entry, key *Key, data interface{}, size int64, softTTL, ttl time.Duration, now time.Time) bool {
+	if entry != nil {
+		replaced := &Entry{Key: entry.Key, Data: entry.Data}
+
+		shard.evictor.Access(entry)
+		entry.setTTL(now, softTTL, ttl)
+		entry.version = set.nextVersion()
+		shard.reschedule(entry)
+
+		entry.Data = data
+		shard.retag(entry, key)
+		delta := shard.resize(entry, size)
+		trimmed := shard.trim(0, 0, entry)
+		shard.keysLock.Unlock()
+
+		set.evicted(trimmed, EvictCapacity)
+		set.resized(delta)
+		set.notifyEvicted([]*Entry{replaced}, EvictReplaced)
+		return false
+	}
+
+	entry = createEntryAt(now, key, 0, data)
+	entry.setTTL(now, softTTL, ttl)
+	entry.size = size
+	trimmed := set.insert(shard, entry)
+
+	shard.keysLock.Unlock()
+
+	set.inserted(size, trimmed)
+
+	return true
+}
+
+// measure returns approximate size of data, values are measured only in byte limited sets
+func (set *cacheSet) measure(shard *cacheShard, data interface{}) (int64, error) {
+	if set.bytesLimit <= 0 {
+		return 0, nil
+	}
+
+	size := estimateSize(data)
+	if size > shard.bytesLimit {
+		return 0, errors.Errorf("struct_cache: value of %d bytes exceeds byte limit of set %q", size, set.name)
+	}
+
+	return size, nil
+}
+
+// insert adds new entry into shard, lock must be held by caller. Returns entries evicted to free space
+func (set *cacheSet) insert(shard *cacheShard, entry *Entry) []*Entry {
+	if len(shard.elements) >= shard.keysLimit && set.logger.IsDebugEnabled() {
+		set.logger.Debug("struct_cache: ATTENTION! Entities count exceeds limit")
+		set.logger.Debugf("struct_cache: trim (max %d current %d)", shard.keysLimit, len(shard.elements))
+	}
+
+	trimmed := shard.trim(1, entry.size, nil)
+	entry.version = set.nextVersion()
+	shard.add(entry)
+
+	return trimmed
+}
+
+// nextVersion returns new version for entry of the set
+func (set *cacheSet) nextVersion() uint64 {
+	return atomic.AddUint64(&set.versions, 1)
+}
+
+// inserted updates set counters and metrics after entry of given size was inserted.
+// Size is passed by caller, entry may be resized by concurrent put once shard lock is released
+func (set *cacheSet) inserted(size int64, trimmed []*Entry) {
+	set.evicted(trimmed, EvictCapacity)
+	atomic.AddInt64(&set.count, 1)
+	set.resized(size)
+	set.metric.IncreaseItemCount(set.name)
+}
+
+// Close stops collectors and writes snapshot file if it is configured
 func (cache *StructCache) Close() {
 	cache.setsLock.RLock()
-	defer cache.setsLock.RUnlock()
 	for _, set := range cache.setsCollection {
 		set.quitCollectorChan <- struct{}{}
 	}
-}
+	cache.setsLock.RUnlock()
 
-// trim remove least recently used elements from cache and leave 'limit - 1' elements, to have a change to put one element
-func (set *cacheSet) trim() {
-	if set.logger.IsDebugEnabled() {
-		set.logger.Debugf("struct_cache: trim (max %d current %d)", set.keysLimit, set.lruList.Len())
+	if cache.snapshotPath != "" {
+		if err := cache.saveSnapshotFile(); err != nil {
+			cache.logger.Warningf("struct_cache: could not save snapshot: %s", err)
+		}
 	}
+}
 
-	for set.lruList.Len() >= set.keysLimit && set.lruList.Len() > 0 {
-		el := set.lruList.Back()
-		if el != nil {
-			if entry, ok := el.Value.(*Entry); ok {
-				delete(set.elements, entry.Key.Pk)
-				set.lruList.Remove(el)
-			}
-		}
+// RemoveCtx removes value by key unless context is done
+func (cache *StructCache) RemoveCtx(ctx context.Context, key *Key) error {
+	if err := ctx.Err(); err != nil {
+		return err
 	}
 
-	set.metric.SetItemCount(set.name, set.lruList.Len())
+	cache.Remove(key)
+
+	return nil
 }
 
 // Remove removes value by key
+// If tags provided, all entries of the set having at least one of them will be removed
+// Otherwise only an entry with given Primary key will be removed
 func (cache *StructCache) Remove(key *Key) {
 	set, exists := cache.getCacheSet(key)
 	if !exists {
 		return
 	}
 
-	set.remove(key)
+	if len(key.Pk) > 0 {
+		set.remove(key, EvictRemoved)
+	}
+
+	for _, tag := range key.Tags {
+		set.removeByTag(tag)
+	}
 }
 
-func (set *cacheSet) remove(key *Key) {
+func (set *cacheSet) remove(key *Key, reason EvictReason) {
 	k := key.Pk
 	if set.logger.IsDebugEnabled() {
 		set.logger.Debugf("struct_cache: REMOVE %q", key)
 	}
 
-	set.keysLock.Lock()
-	if el, ok := set.elements[k]; ok {
-		delete(set.elements, k)
-		set.lruList.Remove(el)
+	shard := set.shard(k)
+
+	shard.keysLock.Lock()
+	entry, ok := shard.elements[k]
+	if ok {
+		shard.removeEntry(entry)
+	}
+	shard.keysLock.Unlock()
+
+	if ok {
+		set.evicted([]*Entry{entry}, reason)
+	}
+}
+
+func (set *cacheSet) removeByTag(tag string) {
+	if set.logger.IsDebugEnabled() {
+		set.logger.Debugf("struct_cache: REMOVE by tag %q from set %q", tag, set.name)
+	}
+
+	for _, shard := range set.shards {
+		set.evicted(shard.removeByTag(tag), EvictRemoved)
+	}
+}
+
+// evicted updates set counters and metrics after entries were removed and calls evict callbacks
+func (set *cacheSet) evicted(entries []*Entry, reason EvictReason) {
+	if len(entries) == 0 {
+		return
+	}
+
+	var size int64
+	for _, entry := range entries {
+		size += entry.size
+	}
+
+	count := atomic.AddInt64(&set.count, -int64(len(entries)))
+	set.metric.SetItemCount(set.name, int(count))
+	set.resized(-size)
+
+	set.notifyEvicted(entries, reason)
+}
+
+// resized updates set size and metric after size of stored values has changed
+func (set *cacheSet) resized(delta int64) {
+	if set.bytesLimit <= 0 {
+		return
 	}
-	set.keysLock.Unlock()
 
-	set.metric.SetItemCount(set.name, set.lruList.Len())
+	bytes := atomic.AddInt64(&set.bytes, delta)
+	set.metric.SetByteCount(set.name, int(bytes))
 }
 
 func (set *cacheSet) collector() {
+	defer close(set.collectorDone)
+
 	for {
 		select {
-		case <-set.ticker.C:
-			set.keysLock.RLock()
-			i := 0
-			for _, el := range set.elements {
-				if i == 1000 {
-					set.keysLock.RUnlock()
-					time.Sleep(time.Millisecond * 10)
-					i = 0
-					set.keysLock.RLock()
-				}
-				i++
-				if entry, ok := el.Value.(*Entry); ok {
-					if entry.IsValid() {
-						continue
-					}
-					i--
-					if set.logger.IsDebugEnabled() {
-						set.logger.Debugf("struct_cache: collector found NOT VALID %q", entry.Key)
-					}
-					set.keysLock.RUnlock()
-					set.remove(entry.Key)
-					set.keysLock.RLock()
-
-				}
-			}
-			set.keysLock.RUnlock()
+		case <-set.ticker.C():
+			set.collect()
 		case <-set.quitCollectorChan:
+			if set.ownTicker {
+				set.ticker.Stop()
+			}
 			return
 		}
 	}
 }
 
+// collect removes expired entries of all shards and reports sweep duration and number of removed entries
+func (set *cacheSet) collect() {
+	ts := time.Now()
+
+	collected := 0
+	for _, shard := range set.shards {
+		collected += set.collectShard(shard)
+	}
+
+	set.metric.AddCollectedCount(set.name, collected)
+	set.metric.ObserveRT(set.labels(map[string]string{
+		metric.LabelSet:       set.name,
+		metric.LabelOperation: "collect",
+	}), metric.SinceMs(ts))
+}
+
+// flush removes all entries from set and returns number of flushed entries
+func (set *cacheSet) flush() int {
+	var count int
+
+	for _, shard := range set.shards {
+		elements, bytes := shard.flush()
+		count += len(elements)
+
+		atomic.AddInt64(&set.count, -int64(len(elements)))
+		set.resized(-bytes)
+
+		if set.hasEvictCallbacks() {
+			entries := make([]*Entry, 0, len(elements))
+			for _, entry := range elements {
+				entries = append(entries, entry)
+			}
+			set.notifyEvicted(entries, EvictFlushed)
+		}
+	}
+
+	return count
+}
+
 // Flush removes all entries from cache and returns number of flushed entries
 func (cache *StructCache) Flush() int {
 	if cache.logger.IsDebugEnabled() {
 		cache.logger.Debug("struct_cache: flush()")
 	}
 
-	cache.setsLock.RLock()
-	defer cache.setsLock.RUnlock()
-
 	var count int
 
-	for _, set := range cache.setsCollection {
-		set.keysLock.Lock()
-		count += set.lruList.Len()
-		set.elements = make(map[string]*list.Element)
-		set.lruList.Init()
-		set.keysLock.Unlock()
+	// callbacks run outside of sets lock, so they can use the cache
+	for _, set := range cache.findSets("") {
+		count += set.flush()
 
-		cache.metric.SetItemCount(set.name, 0)
+		cache.metric.SetItemCount(set.name, set.len())
 	}
 
 	return count
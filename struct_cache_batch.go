@@ -0,0 +1,85 @@
+package cache
+
+import (
+	"time"
+
+	"go-cache/metric"
+)
+
+// StructItem is value with its key for batch put
+type StructItem struct {
+	Key  *Key
+	Data interface{}
+}
+
+// StructResult is result of batch get for one key
+type StructResult struct {
+	Data  interface{}
+	Found bool
+}
+
+// GetMulti returns values by keys, results are keyed by Key.ID()
+func (cache *StructCache) GetMulti(keys []*Key) map[string]StructResult {
+	ts := time.Now()
+
+	results := make(map[string]StructResult, len(keys))
+	for _, key := range keys {
+		data, ok := cache.Get(key)
+		results[key.ID()] = StructResult{Data: data, Found: ok}
+	}
+
+	cache.metric.ObserveRT(map[string]string{
+		metric.LabelSet:       batchSet(keys),
+		metric.LabelOperation: "get_multi",
+	}, metric.SinceMs(ts))
+
+	return results
+}
+
+// PutMulti puts values by keys. Returns errors of failed keys keyed by Key.ID()
+func (cache *StructCache) PutMulti(items []StructItem, ttl time.Duration) map[string]error {
+	ts := time.Now()
+
+	var errs map[string]error
+	keys := make([]*Key, len(items))
+	for i, item := range items {
+		keys[i] = item.Key
+		if err := cache.Put(item.Data, item.Key, ttl); err != nil {
+			if errs == nil {
+				errs = make(map[string]error)
+			}
+			errs[item.Key.ID()] = err
+		}
+	}
+
+	cache.metric.ObserveRT(map[string]string{
+		metric.LabelSet:       batchSet(keys),
+		metric.LabelOperation: "put_multi",
+		metric.LabelIsError:   metric.IsError(firstError(errs)),
+	}, metric.SinceMs(ts))
+
+	return errs
+}
+
+// RemoveMulti removes values by keys
+func (cache *StructCache) RemoveMulti(keys []*Key) {
+	ts := time.Now()
+
+	for _, key := range keys {
+		cache.Remove(key)
+	}
+
+	cache.metric.ObserveRT(map[string]string{
+		metric.LabelSet:       batchSet(keys),
+		metric.LabelOperation: "remove_multi",
+	}, metric.SinceMs(ts))
+}
+
+// firstError returns any error of batch errors, nil if there is none
+func firstError(errs map[string]error) error {
+	for _, err := range errs {
+		return err
+	}
+
+	return nil
+}
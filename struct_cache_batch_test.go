@@ -0,0 +1,58 @@
+package cache
+
+import (
+	"testing"
+	"time"
+
+	"go-cache/metric/dummy"
+)
+
+func TestStructCache_Multi(t *testing.T) {
+	structCache := NewStructCacheObject(8000, nil, dummy.NewMetric())
+
+	k1 := &Key{Set: "set1", Pk: "1"}
+	k2 := &Key{Set: "set1", Pk: "2"}
+	k3 := &Key{Set: "set2", Pk: "3"}
+
+	errs := structCache.PutMulti([]StructItem{
+		{Key: k1, Data: "data1"},
+		{Key: k2, Data: "data2"},
+	}, time.Minute)
+	if len(errs) != 0 {
+		t.Fatalf("Unexpected errors %v", errs)
+	}
+
+	results := structCache.GetMulti([]*Key{k1, k2, k3})
+	if len(results) != 3 {
+		t.Fatalf("Expected 3 results, got %d", len(results))
+	}
+	if r := results[k1.ID()]; !r.Found || r.Data != "data1" {
+		t.Errorf("Unexpected result for %s: %v", k1.ID(), r)
+	}
+	if r := results[k2.ID()]; !r.Found || r.Data != "data2" {
+		t.Errorf("Unexpected result for %s: %v", k2.ID(), r)
+	}
+	if r := results[k3.ID()]; r.Found {
+		t.Errorf("Key %s should not be found", k3.ID())
+	}
+
+	structCache.RemoveMulti([]*Key{k1, k2})
+	if count := structCache.Count(); count != 0 {
+		t.Errorf("Expected empty cache, count %d", count)
+	}
+
+	structCache.SetLimit(0)
+	errs = structCache.PutMulti([]StructItem{{Key: k3, Data: "data3"}}, time.Minute)
+	if errs[k3.ID()] == nil {
+		t.Error("Error of failed put is expected")
+	}
+}
+
+func TestBatchSet(t *testing.T) {
+	if set := batchSet([]*Key{{Set: "a"}, {Set: "a"}}); set != "a" {
+		t.Errorf("Expected set a, got %q", set)
+	}
+	if set := batchSet([]*Key{{Set: "a"}, {Set: "b"}}); set != "*" {
+		t.Errorf("Expected mixed set, got %q", set)
+	}
+}
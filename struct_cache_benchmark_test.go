@@ -218,7 +218,7 @@ func BenchmarkStructCache_ConcurentGetWithoutGC(b *testing.B) {
 
 func BenchmarkStructCache_ConcurentPutWithGC(b *testing.B) {
 	structCache := NewStructCacheObject(50000000, nil, dummy.NewMetric())
-	structCache.ticker = time.NewTicker(time.Millisecond * 10)
+	structCache.defaultOptions.CollectorInterval = time.Millisecond * 10
 
 	for i := 0; i < 500000; i++ {
 		k := &Key{
@@ -248,7 +248,7 @@ func BenchmarkStructCache_ConcurentPutWithGC(b *testing.B) {
 
 func BenchmarkStructCache_ConcurentGet_ManySets_WithGC(b *testing.B) {
 	structCache := NewStructCacheObject(50000000, nil, dummy.NewMetric())
-	structCache.ticker = time.NewTicker(time.Millisecond * 10)
+	structCache.defaultOptions.CollectorInterval = time.Millisecond * 10
 
 	for i := 0; i < 500000; i++ {
 		k := &Key{
@@ -286,7 +286,44 @@ func BenchmarkStructCache_ConcurentGet_ManySets_WithGC(b *testing.B) {
 
 func BenchmarkStructCache_ConcurentGet_SingleSet_WithGC(b *testing.B) {
 	structCache := NewStructCacheObject(50000000, nil, dummy.NewMetric())
-	structCache.ticker = time.NewTicker(time.Millisecond * 10)
+	structCache.defaultOptions.CollectorInterval = time.Millisecond * 10
+
+	for i := 0; i < 500000; i++ {
+		k := &Key{
+			Set: "set1",
+			Pk:  strconv.Itoa(i),
+		}
+		structCache.Put(i, k, defaultTTL)
+	}
+
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			if i > 500000 {
+				i = 1
+			}
+			i++
+
+			k := &Key{
+				Set: "set1",
+				Pk:  strconv.Itoa(i),
+			}
+
+			v, ok := structCache.Get(k)
+			if !ok {
+				b.Fatalf("Get operation is unsuccessfull for %v", k)
+			}
+			if v == nil {
+				b.Fatalf("Got nil value from cache for %v", k)
+			}
+		}
+	})
+}
+
+func BenchmarkStructCache_ConcurentGet_ShardedSingleSet_WithGC(b *testing.B) {
+	structCache := NewStructCacheObject(50000000, nil, dummy.NewMetric())
+	structCache.RegisterShardedCacheSet("set1", 50000000, 32, time.NewTicker(time.Millisecond*10))
 
 	for i := 0; i < 500000; i++ {
 		k := &Key{
@@ -0,0 +1,70 @@
+package cache
+
+import (
+	"time"
+
+	"go-cache/errors"
+	"go-cache/metric"
+)
+
+// Incr atomically adds delta to counter by key and returns new value. Missing counter starts at zero.
+// Counter is stored as int64, ttl counts from the last change
+func (cache *StructCache) Incr(key *Key, delta int64, ttl time.Duration) (int64, error) {
+	set, err := cache.setForPut(key)
+	if err != nil {
+		return 0, err
+	}
+
+	if _, ttl = set.resolveTTL(0, ttl); ttl <= 0 {
+		return 0, errors.New("Cannot put element (ttl or cache limit is not assign)")
+	}
+
+	if cache.logger.IsDebugEnabled() {
+		cache.logger.Debugf("struct_cache: INCR %q by %d with TTL: %s", key, delta, ttl)
+	}
+
+	return set.incr(key, delta, ttl)
+}
+
+// Decr atomically subtracts delta from counter by key and returns new value. Missing counter starts at zero
+func (cache *StructCache) Decr(key *Key, delta int64, ttl time.Duration) (int64, error) {
+	return cache.Incr(key, -delta, ttl)
+}
+
+func (set *cacheSet) incr(key *Key, delta int64, ttl time.Duration) (int64, error) {
+	ts := time.Now()
+	shard := set.shard(key.Pk)
+
+	size, err := set.measure(shard, int64(0))
+	if err != nil {
+		return 0, err
+	}
+
+	now := set.clock.Now()
+
+	shard.keysLock.Lock()
+
+	var counter int64
+
+	entry, ok := shard.elements[key.Pk]
+	if ok && entry.isValidAt(now) {
+		switch value := entry.Data.(type) {
+		case int64:
+			counter = value
+		case negativeValue:
+		default:
+			shard.keysLock.Unlock()
+			return 0, errors.Errorf("struct_cache: value of %q is %T, not counter", key, value)
+		}
+	}
+
+	counter += delta
+	set.store(shard, entry, key, counter, size, 0, ttl, now)
+
+	set.metric.ObserveRT(set.labels(map[string]string{
+		metric.LabelSet:       key.Set,
+		metric.LabelOperation: "incr",
+	}), metric.SinceMs(ts))
+
+	return counter, nil
+}
@@ -0,0 +1,75 @@
+package cache
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"go-cache/metric/dummy"
+)
+
+func TestStructCache_Incr(t *testing.T) {
+	clock := NewFakeClock(time.Now())
+	structCache := NewStructCacheObject(8000, nil, dummy.NewMetric())
+	structCache.SetClock(clock)
+	k := &Key{Set: "counters", Pk: "views"}
+
+	if value, err := structCache.Incr(k, 5, time.Minute); err != nil || value != 5 {
+		t.Fatalf("Missing counter should start at zero, got %d, %v", value, err)
+	}
+	if value, err := structCache.Decr(k, 2, time.Minute); err != nil || value != 3 {
+		t.Errorf("Unexpected counter %d, %v", value, err)
+	}
+	if data, ok := structCache.Get(k); !ok || data != int64(3) {
+		t.Errorf("Counter should be stored as int64, got %#v", data)
+	}
+
+	clock.Advance(time.Minute)
+
+	if value, _ := structCache.Incr(k, 1, time.Minute); value != 1 {
+		t.Errorf("Expired counter should start at zero, got %d", value)
+	}
+}
+
+func TestStructCache_Incr_NotCounter(t *testing.T) {
+	structCache := NewStructCacheObject(8000, nil, dummy.NewMetric())
+	k := &Key{Set: "counters", Pk: "views"}
+
+	structCache.Put("data", k, time.Minute)
+
+	if _, err := structCache.Incr(k, 1, time.Minute); err == nil {
+		t.Error("Incr of not counter value should fail")
+	}
+	if data, _ := structCache.Get(k); data != "data" {
+		t.Errorf("Value should not be changed, got %v", data)
+	}
+
+	structCache.PutNegative(k, time.Minute)
+
+	if value, err := structCache.Incr(k, 1, time.Minute); err != nil || value != 1 {
+		t.Errorf("Negative entry should be counted as zero, got %d, %v", value, err)
+	}
+}
+
+func TestStructCache_Incr_Concurrent(t *testing.T) {
+	structCache := NewStructCacheObject(8000, nil, dummy.NewMetric())
+	k := &Key{Set: "counters", Pk: "views"}
+
+	const workers, increments = 8, 1000
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < increments; j++ {
+				structCache.Incr(k, 1, time.Minute)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if data, _ := structCache.Get(k); data != int64(workers*increments) {
+		t.Errorf("Expected %d, got %v", workers*increments, data)
+	}
+}
@@ -1,14 +1,19 @@
 package cache
 
 import (
+	"context"
+	"sync"
 	"time"
-
-	"go-cache/errors"
 )
 
-// StructCacheDummy implements cache that accept any data but always return null
+// StructCacheDummy implements cache that accept any data but always return null.
+// Values are checked by value safety mode of the set like in StructCache
 type StructCacheDummy struct {
 	logger IStructCacheLogger
+
+	lock               sync.RWMutex
+	valueSafety        map[string]ValueSafety
+	defaultValueSafety ValueSafety
 }
 
 var _ IStructCache = &StructCacheDummy{}
@@ -18,7 +23,10 @@ func NewStructCacheObjectDummy(logger IStructCacheLogger) *StructCacheDummy {
 	if logger == nil {
 		logger = NewNilLogger()
 	}
-	return &StructCacheDummy{logger}
+	return &StructCacheDummy{
+		logger:      logger,
+		valueSafety: make(map[string]ValueSafety),
+	}
 }
 
 func (cache *StructCacheDummy) RegisterCacheSet(setName string, limit int, ticker *time.Ticker) error {
@@ -26,6 +34,68 @@ func (cache *StructCacheDummy) RegisterCacheSet(setName string, limit int, ticke
 	return nil
 }
 
+// RegisterCacheSetWithOptions keeps value safety mode of the set, do nothing else
+func (cache *StructCacheDummy) RegisterCacheSetWithOptions(setName string, options CacheSetOptions) error {
+	cache.logger.Debugf("struct_storage_dummy: registerCacheSetWithOptions(), setName: %s, limit: %d", setName, options.Limit)
+
+	cache.lock.Lock()
+	defer cache.lock.Unlock()
+
+	if _, exists := cache.valueSafety[setName]; exists {
+		return ErrSetAlreadyExists
+	}
+	cache.valueSafety[setName] = options.ValueSafety
+
+	return nil
+}
+
+// SetDefaultCacheSetOptions keeps value safety mode of sets registered implicitly, do nothing else
+func (cache *StructCacheDummy) SetDefaultCacheSetOptions(options CacheSetOptions) {
+	cache.lock.Lock()
+	cache.defaultValueSafety = options.ValueSafety
+	cache.lock.Unlock()
+}
+
+// UnregisterCacheSet forgets value safety mode of the set, do nothing else
+func (cache *StructCacheDummy) UnregisterCacheSet(setName string) error {
+	cache.logger.Debugf("struct_storage_dummy: unregisterCacheSet(), setName: %s", setName)
+
+	cache.lock.Lock()
+	delete(cache.valueSafety, setName)
+	cache.lock.Unlock()
+
+	return nil
+}
+
+// FlushSet returns 0, do nothing
+func (cache *StructCacheDummy) FlushSet(setName string) int {
+	cache.logger.Debugf("struct_storage_dummy: flushSet(), setName: %s", setName)
+	return 0
+}
+
+// Sets returns nil, do nothing
+func (cache *StructCacheDummy) Sets() []SetInfo {
+	cache.logger.Debug("struct_storage_dummy: sets()")
+	return nil
+}
+
+// check returns error if StructCache would reject value by value safety mode of the set
+func (cache *StructCacheDummy) check(data interface{}, key *Key) error {
+	_, err := cache.getValueSafety(key.Set).onPut(data)
+	return err
+}
+
+func (cache *StructCacheDummy) getValueSafety(setName string) ValueSafety {
+	cache.lock.RLock()
+	defer cache.lock.RUnlock()
+
+	if mode, ok := cache.valueSafety[setName]; ok {
+		return mode
+	}
+
+	return cache.defaultValueSafety
+}
+
 // Close do nothing
 func (cache *StructCacheDummy) Close() {
 	cache.logger.Debug("struct_storage_dummy: close()")
@@ -46,11 +116,7 @@ func (cache *StructCacheDummy) Get(key *Key) (data interface{}, ok bool) {
 // Put returns nil, do nothing
 func (cache *StructCacheDummy) Put(data interface{}, key *Key, ttl time.Duration) error {
 	cache.logger.Debugf("struct_storage_dummy: put(), key: %s", key.ID())
-	// For the beginning, let's check if it is pointer.
-	if isPointer(data) {
-		return errors.Errorf("struct_storage: It is prohibited to keep pointers in struct cache. %s.", key.String())
-	}
-	return nil
+	return cache.check(data, key)
 }
 
 // Remove returns nil, do nothing
@@ -58,6 +124,117 @@ func (cache *StructCacheDummy) Remove(key *Key) {
 	cache.logger.Debugf("struct_storage_dummy: remove(), key: %s", key.ID())
 }
 
+// GetCtx returns nil, do nothing
+func (cache *StructCacheDummy) GetCtx(ctx context.Context, key *Key) (data interface{}, ok bool, err error) {
+	if err = ctx.Err(); err != nil {
+		return
+	}
+	data, ok = cache.Get(key)
+	return
+}
+
+// PutCtx checks data like Put, do nothing
+func (cache *StructCacheDummy) PutCtx(ctx context.Context, data interface{}, key *Key, ttl time.Duration) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return cache.Put(data, key, ttl)
+}
+
+// RemoveCtx returns nil, do nothing
+func (cache *StructCacheDummy) RemoveCtx(ctx context.Context, key *Key) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	cache.Remove(key)
+	return nil
+}
+
+// GetMulti returns not found result for every key
+func (cache *StructCacheDummy) GetMulti(keys []*Key) map[string]StructResult {
+	results := make(map[string]StructResult, len(keys))
+	for _, key := range keys {
+		results[key.ID()] = StructResult{}
+	}
+	return results
+}
+
+// PutMulti checks data like Put, do nothing
+func (cache *StructCacheDummy) PutMulti(items []StructItem, ttl time.Duration) map[string]error {
+	var errs map[string]error
+	for _, item := range items {
+		if err := cache.Put(item.Data, item.Key, ttl); err != nil {
+			if errs == nil {
+				errs = make(map[string]error)
+			}
+			errs[item.Key.ID()] = err
+		}
+	}
+	return errs
+}
+
+// RemoveMulti do nothing
+func (cache *StructCacheDummy) RemoveMulti(keys []*Key) {
+	cache.logger.Debugf("struct_storage_dummy: removeMulti(), keys: %d", len(keys))
+}
+
+// PutNegative returns nil, do nothing
+func (cache *StructCacheDummy) PutNegative(key *Key, ttl time.Duration) error {
+	cache.logger.Debugf("struct_storage_dummy: putNegative(), key: %s", key.ID())
+	return nil
+}
+
+// Lookup returns miss, do nothing
+func (cache *StructCacheDummy) Lookup(key *Key) (data interface{}, result LookupResult) {
+	cache.logger.Debugf("struct_storage_dummy: lookup(), key: %s", key.ID())
+	return nil, LookupMiss
+}
+
+// TTL returns false, do nothing
+func (cache *StructCacheDummy) TTL(key *Key) (ttl time.Duration, ok bool) {
+	cache.logger.Debugf("struct_storage_dummy: ttl(), key: %s", key.ID())
+	return
+}
+
+// Touch returns false, do nothing
+func (cache *StructCacheDummy) Touch(key *Key, ttl time.Duration) (ok bool, err error) {
+	cache.logger.Debugf("struct_storage_dummy: touch(), key: %s", key.ID())
+	return
+}
+
+// PutUntil checks value by value safety mode of the set, do nothing else
+func (cache *StructCacheDummy) PutUntil(data interface{}, key *Key, until time.Time) error {
+	cache.logger.Debugf("struct_storage_dummy: putUntil(), key: %s", key.ID())
+	return cache.check(data, key)
+}
+
+// GetVersioned returns nil, do nothing
+func (cache *StructCacheDummy) GetVersioned(key *Key) (data interface{}, version uint64, ok bool) {
+	cache.logger.Debugf("struct_storage_dummy: getVersioned(), key: %s", key.ID())
+	return
+}
+
+// CompareAndSwap accepts only version 0 as there are no entries, value is checked by value safety mode of the set
+func (cache *StructCacheDummy) CompareAndSwap(key *Key, version uint64, data interface{}, ttl time.Duration) error {
+	cache.logger.Debugf("struct_storage_dummy: compareAndSwap(), key: %s, version: %d", key.ID(), version)
+	if version != 0 {
+		return ErrVersionConflict
+	}
+	return cache.check(data, key)
+}
+
+// Incr returns delta as counters are not kept, do nothing
+func (cache *StructCacheDummy) Incr(key *Key, delta int64, ttl time.Duration) (int64, error) {
+	cache.logger.Debugf("struct_storage_dummy: incr(), key: %s, delta: %d", key.ID(), delta)
+	return delta, nil
+}
+
+// Decr returns -delta as counters are not kept, do nothing
+func (cache *StructCacheDummy) Decr(key *Key, delta int64, ttl time.Duration) (int64, error) {
+	cache.logger.Debugf("struct_storage_dummy: decr(), key: %s, delta: %d", key.ID(), delta)
+	return -delta, nil
+}
+
 // Count returns number of cache entries
 func (cache *StructCacheDummy) Count() int {
 	cache.logger.Debug("struct_storage_dummy: count()")
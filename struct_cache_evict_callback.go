@@ -0,0 +1,104 @@
+package cache
+
+import (
+	"sync"
+
+	"go-cache/errors"
+)
+
+// EvictReason tells why entry has left the cache
+type EvictReason int
+
+const (
+	// EvictCapacity entry was evicted to free space for other entries
+	EvictCapacity EvictReason = iota + 1
+	// EvictExpired entry has outlived its TTL
+	EvictExpired
+	// EvictRemoved entry was removed by Remove
+	EvictRemoved
+	// EvictReplaced entry value was replaced by Put
+	EvictReplaced
+	// EvictFlushed entry was removed by Flush
+	EvictFlushed
+)
+
+// String implements fmt.Stringer interface
+func (reason EvictReason) String() string {
+	switch reason {
+	case EvictCapacity:
+		return "capacity"
+	case EvictExpired:
+		return "expired"
+	case EvictRemoved:
+		return "removed"
+	case EvictReplaced:
+		return "replaced"
+	case EvictFlushed:
+		return "flushed"
+	}
+
+	return "unknown"
+}
+
+// EvictCallback is called when entry leaves the cache, it is called outside of cache locks.
+// Value of negative entry is nil
+type EvictCallback func(key *Key, value interface{}, reason EvictReason)
+
+// evictCallbacks is thread safe list of callbacks
+type evictCallbacks struct {
+	lock      sync.RWMutex
+	callbacks []EvictCallback
+}
+
+func (c *evictCallbacks) add(callback EvictCallback) {
+	c.lock.Lock()
+	c.callbacks = append(c.callbacks, callback)
+	c.lock.Unlock()
+}
+
+func (c *evictCallbacks) get() []EvictCallback {
+	c.lock.RLock()
+	callbacks := c.callbacks
+	c.lock.RUnlock()
+
+	return callbacks
+}
+
+// OnEvict registers callback called for entries leaving any set of the cache
+func (cache *StructCache) OnEvict(callback EvictCallback) {
+	cache.onEvict.add(callback)
+}
+
+// OnSetEvict registers callback called for entries leaving given set
+func (cache *StructCache) OnSetEvict(setName string, callback EvictCallback) error {
+	set, exists := cache.getCacheSet(&Key{Set: setName})
+	if !exists {
+		return errors.Errorf("Set %q is not registered", setName)
+	}
+
+	set.onEvict.add(callback)
+
+	return nil
+}
+
+func (set *cacheSet) hasEvictCallbacks() bool {
+	return len(set.onEvict.get()) > 0 || len(set.cacheOnEvict.get()) > 0
+}
+
+// notifyEvicted calls set and cache callbacks for evicted entries
+func (set *cacheSet) notifyEvicted(entries []*Entry, reason EvictReason) {
+	setCallbacks, cacheCallbacks := set.onEvict.get(), set.cacheOnEvict.get()
+	if len(setCallbacks) == 0 && len(cacheCallbacks) == 0 {
+		return
+	}
+
+	for _, entry := range entries {
+		data := publicValue(entry.Data)
+		for _, callback := range setCallbacks {
+			callback(entry.Key, data, reason)
+		}
+		for _, callback := range cacheCallbacks {
+			callback(entry.Key, data, reason)
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package cache
+
+import (
+	"go-cache/metric/dummy"
+	"sync"
+	"testing"
+	"time"
+)
+
+type evictRecorder struct {
+	lock    sync.Mutex
+	reasons map[string]EvictReason
+}
+
+func newEvictRecorder() *evictRecorder {
+	return &evictRecorder{reasons: make(map[string]EvictReason)}
+}
+
+func (r *evictRecorder) callback(key *Key, value interface{}, reason EvictReason) {
+	r.lock.Lock()
+	r.reasons[key.Pk+"="+value.(string)] = reason
+	r.lock.Unlock()
+}
+
+func (r *evictRecorder) assert(tb testing.TB, id string, reason EvictReason) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if got := r.reasons[id]; got != reason {
+		tb.Errorf("Evict reason of %q is not expected: %s, expected: %s", id, got, reason)
+	}
+}
+
+func TestStructCache_OnEvict_Reasons(t *testing.T) {
+	structCache := NewStructCacheObject(8000, nil, dummy.NewMetric())
+	structCache.RegisterCacheSet("set1", 2, nil)
+
+	recorder := newEvictRecorder()
+	structCache.OnEvict(recorder.callback)
+
+	structCache.Put("a", &Key{Set: "set1", Pk: "1"}, time.Minute*5)
+	structCache.Put("b", &Key{Set: "set1", Pk: "1"}, time.Minute*5)
+	recorder.assert(t, "1=a", EvictReplaced)
+
+	structCache.Put("c", &Key{Set: "set1", Pk: "2"}, time.Minute*5)
+	structCache.Put("d", &Key{Set: "set1", Pk: "3"}, time.Minute*5)
+	recorder.assert(t, "1=b", EvictCapacity)
+
+	structCache.Remove(&Key{Set: "set1", Pk: "2"})
+	recorder.assert(t, "2=c", EvictRemoved)
+
+	set, _ := structCache.getCacheSet(&Key{Set: "set1"})
+	set.shard("3").elements["3"].Expires = time.Now().Add(-time.Millisecond)
+	structCache.Get(&Key{Set: "set1", Pk: "3"})
+	recorder.assert(t, "3=d", EvictExpired)
+
+	structCache.Put("e", &Key{Set: "set1", Pk: "4"}, time.Minute*5)
+	structCache.Flush()
+	recorder.assert(t, "4=e", EvictFlushed)
+}
+
+func TestStructCache_OnSetEvict(t *testing.T) {
+	structCache := NewStructCacheObject(8000, nil, dummy.NewMetric())
+
+	if err := structCache.OnSetEvict("set1", func(*Key, interface{}, EvictReason) {}); err == nil {
+		t.Error("Callback for unknown set should be rejected")
+	}
+
+	structCache.RegisterCacheSet("set1", 10, nil)
+	structCache.RegisterCacheSet("set2", 10, nil)
+
+	recorder := newEvictRecorder()
+	if err := structCache.OnSetEvict("set1", recorder.callback); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	structCache.Put("a", &Key{Set: "set1", Pk: "1"}, time.Minute*5)
+	structCache.Put("b", &Key{Set: "set2", Pk: "2"}, time.Minute*5)
+	structCache.Flush()
+
+	recorder.assert(t, "1=a", EvictFlushed)
+	recorder.assert(t, "2=b", 0)
+}
+
+func TestStructCache_OnEvict_Flush_Reentrant(t *testing.T) {
+	structCache := NewStructCacheObject(8000, nil, dummy.NewMetric())
+	structCache.Put("a", &Key{Set: "set1", Pk: "1"}, time.Minute)
+
+	structCache.OnEvict(func(key *Key, value interface{}, reason EvictReason) {
+		structCache.Get(key)
+		structCache.Put(value, &Key{Set: "flushed", Pk: key.Pk}, time.Minute)
+	})
+
+	done := make(chan int)
+	go func() {
+		done <- structCache.Flush()
+	}()
+
+	select {
+	case count := <-done:
+		if count != 1 {
+			t.Errorf("Expected 1 flushed entry, got %d", count)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Flush is blocked by callback using the cache")
+	}
+
+	if data, ok := structCache.Get(&Key{Set: "flushed", Pk: "1"}); !ok || data != "a" {
+		t.Errorf("Callback should put into new set, got %v, %v", data, ok)
+	}
+}
@@ -0,0 +1,373 @@
+package cache
+
+import (
+	"container/heap"
+	"container/list"
+)
+
+// Evictor keeps eviction order of entries of one cache set shard.
+// All methods are called under shard lock, so implementation doesn't need own locking
+type Evictor interface {
+	// Add registers newly inserted entry
+	Add(entry *Entry)
+	// Access registers read or update of entry
+	Access(entry *Entry)
+	// Remove unregisters entry removed from shard (evicted, expired or removed by user)
+	Remove(entry *Entry)
+	// Victim returns entry that should be evicted next, nil if there is nothing to evict
+	Victim() *Entry
+}
+
+// EvictionPolicy creates Evictor for shard holding up to limit entries
+type EvictionPolicy func(limit int) Evictor
+
+var (
+	// EvictionLRU evicts least recently used entry (default)
+	EvictionLRU EvictionPolicy = func(limit int) Evictor { return newListEvictor(true) }
+
+	// EvictionFIFO evicts the oldest inserted entry, reads don't change the order
+	EvictionFIFO EvictionPolicy = func(limit int) Evictor { return newListEvictor(false) }
+
+	// EvictionLFU evicts least frequently used entry, the oldest one among equally used
+	EvictionLFU EvictionPolicy = func(limit int) Evictor { return newLFUEvictor() }
+
+	// EvictionTinyLFU is W-TinyLFU: small LRU window in front of segmented LRU main space,
+	// entries leaving the window are admitted to main space only if they are used more often than main victim
+	EvictionTinyLFU EvictionPolicy = func(limit int) Evictor { return newTinyLFUEvictor(limit) }
+)
+
+// listEvictor implements LRU and FIFO
+type listEvictor struct {
+	list         *list.List
+	moveOnAccess bool
+}
+
+func newListEvictor(moveOnAccess bool) *listEvictor {
+	return &listEvictor{
+		list:         list.New(),
+		moveOnAccess: moveOnAccess,
+	}
+}
+
+func (e *listEvictor) Add(entry *Entry) {
+	entry.evictorNode = e.list.PushFront(entry)
+}
+
+func (e *listEvictor) Access(entry *Entry) {
+	if !e.moveOnAccess {
+		return
+	}
+	if el, ok := entry.evictorNode.(*list.Element); ok {
+		e.list.MoveToFront(el)
+	}
+}
+
+func (e *listEvictor) Remove(entry *Entry) {
+	if el, ok := entry.evictorNode.(*list.Element); ok {
+		e.list.Remove(el)
+		entry.evictorNode = nil
+	}
+}
+
+func (e *listEvictor) Victim() *Entry {
+	if el := e.list.Back(); el != nil {
+		return el.Value.(*Entry)
+	}
+	return nil
+}
+
+// lfuItem is heap item of lfuEvictor
+type lfuItem struct {
+	entry *Entry
+	freq  uint64
+	seq   uint64
+	index int
+}
+
+// lfuHeap is min-heap ordered by frequency, then by last access
+type lfuHeap []*lfuItem
+
+func (h lfuHeap) Len() int { return len(h) }
+
+func (h lfuHeap) Less(i, j int) bool {
+	if h[i].freq == h[j].freq {
+		return h[i].seq < h[j].seq
+	}
+	return h[i].freq < h[j].freq
+}
+
+func (h lfuHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+func (h *lfuHeap) Push(x interface{}) {
+	item := x.(*lfuItem)
+	item.index = len(*h)
+	*h = append(*h, item)
+}
+
+func (h *lfuHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	item := old[n-1]
+	old[n-1] = nil
+	*h = old[:n-1]
+	return item
+}
+
+// lfuEvictor implements LFU
+type lfuEvictor struct {
+	heap lfuHeap
+	seq  uint64
+}
+
+func newLFUEvictor() *lfuEvictor {
+	return &lfuEvictor{}
+}
+
+func (e *lfuEvictor) Add(entry *Entry) {
+	e.seq++
+	item := &lfuItem{entry: entry, freq: 1, seq: e.seq}
+	entry.evictorNode = item
+	heap.Push(&e.heap, item)
+}
+
+func (e *lfuEvictor) Access(entry *Entry) {
+	if item, ok := entry.evictorNode.(*lfuItem); ok {
+		e.seq++
+		item.freq++
+		item.seq = e.seq
+		heap.Fix(&e.heap, item.index)
+	}
+}
+
+func (e *lfuEvictor) Remove(entry *Entry) {
+	if item, ok := entry.evictorNode.(*lfuItem); ok {
+		heap.Remove(&e.heap, item.index)
+		entry.evictorNode = nil
+	}
+}
+
+func (e *lfuEvictor) Victim() *Entry {
+	if len(e.heap) == 0 {
+		return nil
+	}
+	return e.heap[0].entry
+}
+
+const (
+	tinyLFUWindow = iota
+	tinyLFUProbation
+	tinyLFUProtected
+)
+
+// tinyLFUNode is position of entry in tinyLFUEvictor
+type tinyLFUNode struct {
+	el      *list.Element
+	segment int
+}
+
+// tinyLFUEvictor implements W-TinyLFU
+type tinyLFUEvictor struct {
+	window    *list.List
+	probation *list.List
+	protected *list.List
+
+	windowLimit    int
+	protectedLimit int
+
+	// candidate is the last entry moved from window to probation, it competes with probation victim
+	candidate *Entry
+	sketch    *countMinSketch
+}
+
+func newTinyLFUEvictor(limit int) *tinyLFUEvictor {
+	windowLimit := limit / 100
+	if windowLimit < 1 {
+		windowLimit = 1
+	}
+
+	return &tinyLFUEvictor{
+		window:         list.New(),
+		probation:      list.New(),
+		protected:      list.New(),
+		windowLimit:    windowLimit,
+		protectedLimit: (limit - windowLimit) * 80 / 100,
+		sketch:         newCountMinSketch(limit),
+	}
+}
+
+func (e *tinyLFUEvictor) segmentList(segment int) *list.List {
+	switch segment {
+	case tinyLFUWindow:
+		return e.window
+	case tinyLFUProbation:
+		return e.probation
+	default:
+		return e.protected
+	}
+}
+
+func (e *tinyLFUEvictor) Add(entry *Entry) {
+	e.sketch.increment(entry.Key.Pk)
+	entry.evictorNode = &tinyLFUNode{el: e.window.PushFront(entry), segment: tinyLFUWindow}
+
+	if e.window.Len() > e.windowLimit {
+		el := e.window.Back()
+		demoted := el.Value.(*Entry)
+		e.window.Remove(el)
+		demoted.evictorNode = &tinyLFUNode{el: e.probation.PushFront(demoted), segment: tinyLFUProbation}
+		e.candidate = demoted
+	}
+}
+
+func (e *tinyLFUEvictor) Access(entry *Entry) {
+	node, ok := entry.evictorNode.(*tinyLFUNode)
+	if !ok {
+		return
+	}
+
+	e.sketch.increment(entry.Key.Pk)
+
+	switch node.segment {
+	case tinyLFUWindow, tinyLFUProtected:
+		e.segmentList(node.segment).MoveToFront(node.el)
+	case tinyLFUProbation:
+		if e.candidate == entry {
+			e.candidate = nil
+		}
+		e.probation.Remove(node.el)
+		node.el = e.protected.PushFront(entry)
+		node.segment = tinyLFUProtected
+
+		if e.protected.Len() > e.protectedLimit {
+			el := e.protected.Back()
+			demoted := el.Value.(*Entry)
+			e.protected.Remove(el)
+			demoted.evictorNode = &tinyLFUNode{el: e.probation.PushFront(demoted), segment: tinyLFUProbation}
+		}
+	}
+}
+
+func (e *tinyLFUEvictor) Remove(entry *Entry) {
+	if node, ok := entry.evictorNode.(*tinyLFUNode); ok {
+		e.segmentList(node.segment).Remove(node.el)
+		entry.evictorNode = nil
+	}
+	if e.candidate == entry {
+		e.candidate = nil
+	}
+}
+
+func (e *tinyLFUEvictor) Victim() *Entry {
+	victim := e.mainVictim()
+	if victim == nil {
+		if e.candidate != nil {
+			return e.candidate
+		}
+		if el := e.window.Back(); el != nil {
+			return el.Value.(*Entry)
+		}
+		return nil
+	}
+
+	// admission filter: the candidate stays only if it is used more often than the victim
+	if e.candidate == nil || e.sketch.estimate(e.candidate.Key.Pk) > e.sketch.estimate(victim.Key.Pk) {
+		return victim
+	}
+
+	return e.candidate
+}
+
+// mainVictim returns least recently used entry of main space except the candidate
+func (e *tinyLFUEvictor) mainVictim() *Entry {
+	for _, segment := range []*list.List{e.probation, e.protected} {
+		for el := segment.Back(); el != nil; el = el.Prev() {
+			if entry := el.Value.(*Entry); entry != e.candidate {
+				return entry
+			}
+		}
+	}
+
+	return nil
+}
+
+const sketchDepth = 4
+
+// countMinSketch estimates access frequency of keys, counters are halved periodically to forget old history
+type countMinSketch struct {
+	rows      [sketchDepth][]uint8
+	mask      uint32
+	additions int
+	resetAt   int
+}
+
+func newCountMinSketch(limit int) *countMinSketch {
+	width := 16
+	for width < limit {
+		width <<= 1
+	}
+
+	sketch := &countMinSketch{
+		mask:    uint32(width - 1),
+		resetAt: 10 * width,
+	}
+	for i := range sketch.rows {
+		sketch.rows[i] = make([]uint8, width)
+	}
+
+	return sketch
+}
+
+func (s *countMinSketch) index(hash uint64, row int) uint32 {
+	h1, h2 := uint32(hash), uint32(hash>>32)|1
+	return (h1 + uint32(row)*h2) & s.mask
+}
+
+func (s *countMinSketch) increment(pk string) {
+	hash := hashPk(pk)
+	for i := range s.rows {
+		if idx := s.index(hash, i); s.rows[i][idx] < 255 {
+			s.rows[i][idx]++
+		}
+	}
+
+	s.additions++
+	if s.additions >= s.resetAt {
+		s.reset()
+	}
+}
+
+func (s *countMinSketch) estimate(pk string) uint8 {
+	hash := hashPk(pk)
+	min := uint8(255)
+	for i := range s.rows {
+		if v := s.rows[i][s.index(hash, i)]; v < min {
+			min = v
+		}
+	}
+
+	return min
+}
+
+func (s *countMinSketch) reset() {
+	for i := range s.rows {
+		for j := range s.rows[i] {
+			s.rows[i][j] >>= 1
+		}
+	}
+	s.additions /= 2
+}
+
+// hashPk returns 64-bit FNV-1a hash of primary key
+func hashPk(pk string) uint64 {
+	var hash uint64 = 14695981039346656037
+	for i := 0; i < len(pk); i++ {
+		hash ^= uint64(pk[i])
+		hash *= 1099511628211
+	}
+
+	return hash
+}
@@ -0,0 +1,68 @@
+package cache
+
+import (
+	"go-cache/metric/dummy"
+	"math/rand"
+	"strconv"
+	"testing"
+)
+
+// benchmarkHitRatio replays zipf-distributed reads mixed with sequential scans and reports hit ratio
+func benchmarkHitRatio(b *testing.B, policy EvictionPolicy, scan bool) {
+	structCache := NewStructCacheObject(8000, nil, dummy.NewMetric())
+	structCache.RegisterCacheSetWithPolicy("set1", 1000, policy, nil)
+
+	zipf := rand.NewZipf(rand.New(rand.NewSource(42)), 1.1, 1, 100000)
+	hits := 0
+	scanPos := 0
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		pk := strconv.FormatUint(zipf.Uint64(), 10)
+		if scan && i%2 == 0 {
+			scanPos++
+			pk = "scan" + strconv.Itoa(scanPos)
+		}
+
+		k := &Key{Set: "set1", Pk: pk}
+		if _, ok := structCache.Get(k); ok {
+			hits++
+			continue
+		}
+		structCache.Put(i, k, defaultTTL)
+	}
+
+	b.ReportMetric(float64(hits)/float64(b.N)*100, "hit%")
+}
+
+func BenchmarkStructCache_HitRatio_Zipf_LRU(b *testing.B) {
+	benchmarkHitRatio(b, EvictionLRU, false)
+}
+
+func BenchmarkStructCache_HitRatio_Zipf_FIFO(b *testing.B) {
+	benchmarkHitRatio(b, EvictionFIFO, false)
+}
+
+func BenchmarkStructCache_HitRatio_Zipf_LFU(b *testing.B) {
+	benchmarkHitRatio(b, EvictionLFU, false)
+}
+
+func BenchmarkStructCache_HitRatio_Zipf_TinyLFU(b *testing.B) {
+	benchmarkHitRatio(b, EvictionTinyLFU, false)
+}
+
+func BenchmarkStructCache_HitRatio_ZipfWithScan_LRU(b *testing.B) {
+	benchmarkHitRatio(b, EvictionLRU, true)
+}
+
+func BenchmarkStructCache_HitRatio_ZipfWithScan_FIFO(b *testing.B) {
+	benchmarkHitRatio(b, EvictionFIFO, true)
+}
+
+func BenchmarkStructCache_HitRatio_ZipfWithScan_LFU(b *testing.B) {
+	benchmarkHitRatio(b, EvictionLFU, true)
+}
+
+func BenchmarkStructCache_HitRatio_ZipfWithScan_TinyLFU(b *testing.B) {
+	benchmarkHitRatio(b, EvictionTinyLFU, true)
+}
@@ -0,0 +1,123 @@
+package cache
+
+import (
+	"go-cache/metric/dummy"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func fillEvictionTestSet(tb testing.TB, policy EvictionPolicy) *StructCache {
+	structCache := NewStructCacheObject(8000, nil, dummy.NewMetric())
+	if err := structCache.RegisterCacheSetWithPolicy("set1", 3, policy, nil); err != nil {
+		tb.Fatalf("Unexpected error: %s", err)
+	}
+
+	for i := 1; i <= 3; i++ {
+		structCache.Put(i, &Key{Set: "set1", Pk: strconv.Itoa(i)}, time.Minute*5)
+	}
+
+	return structCache
+}
+
+func assertKeyExists(tb testing.TB, structCache *StructCache, pk string, exists bool) {
+	if _, find := structCache.Get(&Key{Set: "set1", Pk: pk}); find != exists {
+		tb.Errorf("Key %q exists: %t, expected: %t", pk, find, exists)
+	}
+}
+
+func TestStructCache_EvictionLRU(t *testing.T) {
+	structCache := fillEvictionTestSet(t, EvictionLRU)
+
+	assertKeyExists(t, structCache, "1", true)
+	structCache.Put(4, &Key{Set: "set1", Pk: "4"}, time.Minute*5)
+
+	assertKeyExists(t, structCache, "2", false)
+	assertKeyExists(t, structCache, "1", true)
+	assertKeyExists(t, structCache, "4", true)
+}
+
+func TestStructCache_EvictionFIFO(t *testing.T) {
+	structCache := fillEvictionTestSet(t, EvictionFIFO)
+
+	assertKeyExists(t, structCache, "1", true)
+	structCache.Put(4, &Key{Set: "set1", Pk: "4"}, time.Minute*5)
+
+	assertKeyExists(t, structCache, "1", false)
+	assertKeyExists(t, structCache, "2", true)
+	assertKeyExists(t, structCache, "4", true)
+}
+
+func TestStructCache_EvictionLFU(t *testing.T) {
+	structCache := fillEvictionTestSet(t, EvictionLFU)
+
+	assertKeyExists(t, structCache, "1", true)
+	assertKeyExists(t, structCache, "1", true)
+	assertKeyExists(t, structCache, "3", true)
+	structCache.Put(4, &Key{Set: "set1", Pk: "4"}, time.Minute*5)
+
+	assertKeyExists(t, structCache, "2", false)
+	assertKeyExists(t, structCache, "1", true)
+	assertKeyExists(t, structCache, "3", true)
+}
+
+func countHotKeysAfterScan(policy EvictionPolicy) int {
+	structCache := NewStructCacheObject(8000, nil, dummy.NewMetric())
+	structCache.RegisterCacheSetWithPolicy("set1", 100, policy, nil)
+
+	for i := 0; i < 50; i++ {
+		structCache.Put(i, &Key{Set: "set1", Pk: "hot" + strconv.Itoa(i)}, time.Minute*5)
+	}
+	for round := 0; round < 5; round++ {
+		for i := 0; i < 50; i++ {
+			structCache.Get(&Key{Set: "set1", Pk: "hot" + strconv.Itoa(i)})
+		}
+	}
+
+	for i := 0; i < 1000; i++ {
+		structCache.Put(i, &Key{Set: "set1", Pk: "scan" + strconv.Itoa(i)}, time.Minute*5)
+	}
+
+	hot := 0
+	for i := 0; i < 50; i++ {
+		if _, find := structCache.Get(&Key{Set: "set1", Pk: "hot" + strconv.Itoa(i)}); find {
+			hot++
+		}
+	}
+
+	return hot
+}
+
+func TestStructCache_EvictionTinyLFU_ScanResistance(t *testing.T) {
+	if hot := countHotKeysAfterScan(EvictionLRU); hot != 0 {
+		t.Errorf("LRU should lose hot keys after scan, kept: %d", hot)
+	}
+
+	if hot := countHotKeysAfterScan(EvictionTinyLFU); hot < 45 {
+		t.Errorf("TinyLFU should keep hot keys after scan, kept: %d", hot)
+	}
+}
+
+func TestStructCache_Eviction_FlushAndRemove(t *testing.T) {
+	for name, policy := range map[string]EvictionPolicy{
+		"lru":     EvictionLRU,
+		"fifo":    EvictionFIFO,
+		"lfu":     EvictionLFU,
+		"tinylfu": EvictionTinyLFU,
+	} {
+		structCache := fillEvictionTestSet(t, policy)
+
+		structCache.Remove(&Key{Set: "set1", Pk: "2"})
+		if cnt := structCache.Count(); cnt != 2 {
+			t.Errorf("%s: count is not expected: %d", name, cnt)
+		}
+
+		structCache.Flush()
+		for i := 0; i < 10; i++ {
+			structCache.Put(i, &Key{Set: "set1", Pk: strconv.Itoa(i)}, time.Minute*5)
+		}
+		if cnt := structCache.Count(); cnt != 3 {
+			t.Errorf("%s: count is not expected: %d", name, cnt)
+		}
+	}
+}
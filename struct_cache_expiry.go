@@ -0,0 +1,102 @@
+package cache
+
+import (
+	"container/heap"
+	"time"
+)
+
+// collectBatch is max number of expired entries removed under one shard lock
+const collectBatch = 1000
+
+// expiryHeap is min-heap of shard entries ordered by expiration
+type expiryHeap []*Entry
+
+func (h expiryHeap) Len() int { return len(h) }
+
+func (h expiryHeap) Less(i, j int) bool {
+	return h[i].expiresAt().Before(h[j].expiresAt())
+}
+
+func (h expiryHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].expiryIndex = i
+	h[j].expiryIndex = j
+}
+
+func (h *expiryHeap) Push(x interface{}) {
+	entry := x.(*Entry)
+	entry.expiryIndex = len(*h)
+	*h = append(*h, entry)
+}
+
+func (h *expiryHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	entry := old[n-1]
+	old[n-1] = nil
+	entry.expiryIndex = -1
+	*h = old[:n-1]
+	return entry
+}
+
+// schedule adds entry into expiry index, lock must be held by caller
+func (shard *cacheShard) schedule(entry *Entry) {
+	heap.Push(&shard.expiry, entry)
+}
+
+// reschedule moves entry in expiry index after its TTL was changed, lock must be held by caller
+func (shard *cacheShard) reschedule(entry *Entry) {
+	if entry.expiryIndex >= 0 && entry.expiryIndex < len(shard.expiry) && shard.expiry[entry.expiryIndex] == entry {
+		heap.Fix(&shard.expiry, entry.expiryIndex)
+	}
+}
+
+// unschedule removes entry from expiry index, lock must be held by caller
+func (shard *cacheShard) unschedule(entry *Entry) {
+	if entry.expiryIndex >= 0 && entry.expiryIndex < len(shard.expiry) && shard.expiry[entry.expiryIndex] == entry {
+		heap.Remove(&shard.expiry, entry.expiryIndex)
+	}
+}
+
+// removeExpired removes up to limit entries expired at now, lock must be held by caller.
+// Returns removed entries
+func (shard *cacheShard) removeExpired(now time.Time, limit int) []*Entry {
+	var removed []*Entry
+
+	for len(shard.expiry) > 0 && len(removed) < limit {
+		entry := shard.expiry[0]
+		if entry.isValidAt(now) {
+			break
+		}
+		shard.removeEntry(entry)
+		removed = append(removed, entry)
+	}
+
+	return removed
+}
+
+// collectShard removes expired entries of shard in batches, shard lock is released between batches.
+// Returns number of removed entries
+func (set *cacheSet) collectShard(shard *cacheShard) int {
+	now := set.clock.Now()
+	collected := 0
+
+	for {
+		shard.keysLock.Lock()
+		expired := shard.removeExpired(now, collectBatch)
+		shard.keysLock.Unlock()
+
+		if set.logger.IsDebugEnabled() {
+			for _, entry := range expired {
+				set.logger.Debugf("struct_cache: collector found NOT VALID %q", entry.Key)
+			}
+		}
+
+		set.evicted(expired, EvictExpired)
+		collected += len(expired)
+
+		if len(expired) < collectBatch {
+			return collected
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package cache
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"go-cache/metric/dummy"
+)
+
+func TestCacheShard_RemoveExpired(t *testing.T) {
+	clock := NewFakeClock(time.Now())
+	structCache := NewStructCacheObject(100, nil, dummy.NewMetric())
+	structCache.SetClock(clock)
+
+	for i := 0; i < 10; i++ {
+		structCache.Put(i, &Key{Set: "set1", Pk: strconv.Itoa(i)}, time.Duration(10-i)*time.Second)
+	}
+
+	// prolonged entry has to be moved in expiry index
+	structCache.Put(9, &Key{Set: "set1", Pk: "9"}, time.Hour)
+
+	set, _ := structCache.getCacheSet(&Key{Set: "set1"})
+	shard := set.shards[0]
+
+	clock.Advance(5 * time.Second)
+	shard.keysLock.Lock()
+	expired := shard.removeExpired(clock.Now(), 3)
+	shard.keysLock.Unlock()
+
+	if len(expired) != 3 {
+		t.Fatalf("Expected 3 entries in batch, got %d", len(expired))
+	}
+	for i, entry := range expired {
+		if expected := strconv.Itoa(8 - i); entry.Key.Pk != expected {
+			t.Errorf("Expected %q to expire, got %q", expected, entry.Key.Pk)
+		}
+	}
+
+	set.evicted(expired, EvictExpired)
+	if collected := set.collectShard(shard); collected != 1 {
+		t.Errorf("Expected 1 collected entry, got %d", collected)
+	}
+	if count := structCache.Count(); count != 6 {
+		t.Errorf("Expected 6 entries, got %d", count)
+	}
+
+	clock.Advance(time.Minute)
+	if collected := set.collectShard(shard); collected != 5 {
+		t.Errorf("Expected 5 collected entries, got %d", collected)
+	}
+	if _, ok := structCache.Get(&Key{Set: "set1", Pk: "9"}); !ok {
+		t.Error("Prolonged entry should stay")
+	}
+	if len(shard.expiry) != 1 {
+		t.Errorf("Expected 1 entry in expiry index, got %d", len(shard.expiry))
+	}
+}
+
+func TestCacheShard_Expiry_Remove(t *testing.T) {
+	structCache := NewStructCacheObject(100, nil, dummy.NewMetric())
+
+	for i := 0; i < 10; i++ {
+		structCache.Put(i, &Key{Set: "set1", Pk: strconv.Itoa(i), Tags: []string{"tag" + strconv.Itoa(i%2)}}, time.Minute)
+	}
+
+	structCache.Remove(&Key{Set: "set1", Pk: "3"})
+	structCache.Remove(&Key{Set: "set1", Tags: []string{"tag0"}})
+
+	set, _ := structCache.getCacheSet(&Key{Set: "set1"})
+	shard := set.shards[0]
+	if len(shard.expiry) != len(shard.elements) {
+		t.Errorf("Expiry index has %d entries, shard has %d", len(shard.expiry), len(shard.elements))
+	}
+	for i, entry := range shard.expiry {
+		if entry.expiryIndex != i {
+			t.Errorf("Entry %q has index %d, expected %d", entry.Key.Pk, entry.expiryIndex, i)
+		}
+	}
+}
@@ -0,0 +1,181 @@
+package cache
+
+import (
+	"path"
+	"regexp"
+	"sort"
+	"time"
+
+	"go-cache/errors"
+)
+
+// cursorSep separates set and primary key in FindKeys cursor
+const cursorSep = "\x00"
+
+// FindOptions filters keys of StructCache
+type FindOptions struct {
+	// Set limits search to one set, empty means all sets
+	Set string
+	// Pattern is glob matched against Key.Pk (see path.Match), empty matches all keys
+	Pattern string
+	// Regexp is regular expression matched against Key.Pk, it is used instead of Pattern if set
+	Regexp string
+	// Cursor continues search after the last key of previous page, empty starts from the beginning
+	Cursor string
+	// Limit is max number of keys of the page, 0 means no limit
+	Limit int
+}
+
+// FoundKey is key found by FindKeys with its entry dates
+type FoundKey struct {
+	Key
+	CreateDate time.Time
+	// TTL is remaining time to live
+	TTL time.Duration
+}
+
+// keyMatcher reports whether primary key matches FindOptions
+type keyMatcher func(pk string) bool
+
+func (options FindOptions) matcher() (keyMatcher, error) {
+	if options.Regexp != "" {
+		re, err := regexp.Compile(options.Regexp)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid regexp %q", options.Regexp)
+		}
+		return re.MatchString, nil
+	}
+
+	if options.Pattern != "" {
+		if _, err := path.Match(options.Pattern, ""); err != nil {
+			return nil, errors.Wrapf(err, "invalid pattern %q", options.Pattern)
+		}
+		return func(pk string) bool {
+			ok, _ := path.Match(options.Pattern, pk)
+			return ok
+		}, nil
+	}
+
+	return func(string) bool { return true }, nil
+}
+
+// FindKeys returns not expired keys matching options ordered by set and primary key,
+// and cursor of the next page (empty if there are no more keys). Keys known to be absent are skipped
+func (cache *StructCache) FindKeys(options FindOptions) ([]FoundKey, string, error) {
+	match, err := options.matcher()
+	if err != nil {
+		return nil, "", err
+	}
+
+	var found []FoundKey
+	for _, set := range cache.findSets(options.Set) {
+		found = set.findKeys(found, match, options.Cursor)
+	}
+
+	sort.Slice(found, func(i, j int) bool {
+		return cursorOf(&found[i].Key) < cursorOf(&found[j].Key)
+	})
+
+	if options.Limit <= 0 || len(found) <= options.Limit {
+		return found, "", nil
+	}
+
+	found = found[:options.Limit]
+
+	return found, cursorOf(&found[len(found)-1].Key), nil
+}
+
+// RemoveMatching removes all keys matching options, Cursor and Limit are ignored.
+// Returns number of removed keys
+func (cache *StructCache) RemoveMatching(options FindOptions) (int, error) {
+	match, err := options.matcher()
+	if err != nil {
+		return 0, err
+	}
+
+	removed := 0
+	for _, set := range cache.findSets(options.Set) {
+		removed += set.removeMatching(match)
+	}
+
+	if cache.logger.IsDebugEnabled() {
+		cache.logger.Debugf("struct_cache: REMOVE %d entries matching %+v", removed, options)
+	}
+
+	return removed, nil
+}
+
+// findSets returns set with given name or all sets if name is empty
+func (cache *StructCache) findSets(name string) []*cacheSet {
+	cache.setsLock.RLock()
+	defer cache.setsLock.RUnlock()
+
+	if name != "" {
+		if set, ok := cache.setsCollection[name]; ok {
+			return []*cacheSet{set}
+		}
+		return nil
+	}
+
+	sets := make([]*cacheSet, 0, len(cache.setsCollection))
+	for _, set := range cache.setsCollection {
+		sets = append(sets, set)
+	}
+
+	return sets
+}
+
+func (set *cacheSet) findKeys(found []FoundKey, match keyMatcher, cursor string) []FoundKey {
+	now := set.clock.Now()
+
+	for _, shard := range set.shards {
+		shard.keysLock.RLock()
+		for pk, entry := range shard.elements {
+			if !entry.isValidAt(now) || !match(pk) {
+				continue
+			}
+			if _, isNegative := entry.Data.(negativeValue); isNegative {
+				continue
+			}
+			if cursor != "" && cursorOf(entry.Key) <= cursor {
+				continue
+			}
+
+			found = append(found, FoundKey{
+				Key:        *entry.Key,
+				CreateDate: entry.CreateDate,
+				TTL:        entry.expiresAt().Sub(now),
+			})
+		}
+		shard.keysLock.RUnlock()
+	}
+
+	return found
+}
+
+func (set *cacheSet) removeMatching(match keyMatcher) int {
+	removed := 0
+
+	for _, shard := range set.shards {
+		var entries []*Entry
+
+		shard.keysLock.Lock()
+		for pk, entry := range shard.elements {
+			if match(pk) {
+				shard.removeEntry(entry)
+				entries = append(entries, entry)
+			}
+		}
+		shard.keysLock.Unlock()
+
+		set.evicted(entries, EvictRemoved)
+		removed += len(entries)
+	}
+
+	return removed
+}
+
+// cursorOf returns position of key in FindKeys order
+func cursorOf(key *Key) string {
+	return key.Set + cursorSep + key.Pk
+}
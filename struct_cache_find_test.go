@@ -0,0 +1,116 @@
+package cache
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"go-cache/metric/dummy"
+)
+
+func newFindTestCache(t *testing.T) *StructCache {
+	structCache := NewStructCacheObject(8000, nil, dummy.NewMetric())
+	for i := 0; i < 10; i++ {
+		structCache.Put(i, &Key{Set: "products", Pk: "product:" + strconv.Itoa(i)}, time.Minute)
+		structCache.Put(i, &Key{Set: "users", Pk: "user:" + strconv.Itoa(i)}, time.Hour)
+	}
+
+	return structCache
+}
+
+func TestStructCache_FindKeys_Filter(t *testing.T) {
+	structCache := newFindTestCache(t)
+
+	found, cursor, err := structCache.FindKeys(FindOptions{Set: "products", Pattern: "product:[1-3]"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cursor != "" {
+		t.Errorf("Unexpected cursor %q", cursor)
+	}
+	if len(found) != 3 {
+		t.Fatalf("Expected 3 keys, got %d", len(found))
+	}
+	for i, key := range found {
+		if key.Set != "products" || key.Pk != "product:"+strconv.Itoa(i+1) {
+			t.Errorf("Unexpected key %v", key.Key)
+		}
+		if key.TTL <= 0 || key.TTL > time.Minute {
+			t.Errorf("Unexpected TTL %s", key.TTL)
+		}
+		if key.CreateDate.IsZero() {
+			t.Error("Create date is expected")
+		}
+	}
+
+	found, _, err = structCache.FindKeys(FindOptions{Regexp: `^(user|product):[05]$`})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(found) != 4 {
+		t.Errorf("Expected 4 keys, got %d", len(found))
+	}
+
+	if _, _, err := structCache.FindKeys(FindOptions{Regexp: "("}); err == nil {
+		t.Error("Invalid regexp should be rejected")
+	}
+	if _, _, err := structCache.FindKeys(FindOptions{Pattern: "["}); err == nil {
+		t.Error("Invalid pattern should be rejected")
+	}
+}
+
+func TestStructCache_FindKeys_Cursor(t *testing.T) {
+	structCache := newFindTestCache(t)
+
+	var (
+		pages  int
+		keys   []string
+		cursor string
+	)
+	for {
+		found, next, err := structCache.FindKeys(FindOptions{Cursor: cursor, Limit: 6})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(found) > 6 {
+			t.Fatalf("Page is over limit: %d", len(found))
+		}
+		for _, key := range found {
+			keys = append(keys, key.Set+"/"+key.Pk)
+		}
+		pages++
+
+		if next == "" {
+			break
+		}
+		cursor = next
+	}
+
+	if pages != 4 || len(keys) != 20 {
+		t.Fatalf("Expected 20 keys on 4 pages, got %d on %d", len(keys), pages)
+	}
+	for i := 1; i < len(keys); i++ {
+		if keys[i-1] >= keys[i] {
+			t.Errorf("Keys are not ordered: %q >= %q", keys[i-1], keys[i])
+		}
+	}
+}
+
+func TestStructCache_RemoveMatching(t *testing.T) {
+	structCache := newFindTestCache(t)
+
+	removed, err := structCache.RemoveMatching(FindOptions{Set: "users", Pattern: "user:*", Limit: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if removed != 10 {
+		t.Errorf("Expected 10 removed keys, got %d", removed)
+	}
+	if count := structCache.Count(); count != 10 {
+		t.Errorf("Expected 10 keys left, got %d", count)
+	}
+
+	if removed, _ := structCache.RemoveMatching(FindOptions{Set: "unknown"}); removed != 0 {
+		t.Errorf("Nothing should be removed from unknown set, got %d", removed)
+	}
+}
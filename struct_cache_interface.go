@@ -1,6 +1,7 @@
 package cache
 
 import (
+	"context"
 	"time"
 )
 
@@ -8,10 +9,28 @@ import (
 type IStructCache interface {
 	IFlushable
 	RegisterCacheSet(setName string, limit int, ticker *time.Ticker) error
+	UnregisterCacheSet(setName string) error
+	FlushSet(setName string) int
+	Sets() []SetInfo
 	Get(key *Key) (data interface{}, ok bool)
 	GetWithTime(key *Key) (data interface{}, dt time.Time, ok bool)
 	Put(data interface{}, key *Key, ttl time.Duration) error
 	Remove(key *Key)
+	GetCtx(ctx context.Context, key *Key) (data interface{}, ok bool, err error)
+	PutCtx(ctx context.Context, data interface{}, key *Key, ttl time.Duration) error
+	RemoveCtx(ctx context.Context, key *Key) error
+	GetMulti(keys []*Key) map[string]StructResult
+	PutMulti(items []StructItem, ttl time.Duration) map[string]error
+	RemoveMulti(keys []*Key)
+	PutNegative(key *Key, ttl time.Duration) error
+	Lookup(key *Key) (data interface{}, result LookupResult)
+	TTL(key *Key) (ttl time.Duration, ok bool)
+	Touch(key *Key, ttl time.Duration) (ok bool, err error)
+	PutUntil(data interface{}, key *Key, until time.Time) error
+	GetVersioned(key *Key) (data interface{}, version uint64, ok bool)
+	CompareAndSwap(key *Key, version uint64, data interface{}, ttl time.Duration) error
+	Incr(key *Key, delta int64, ttl time.Duration) (int64, error)
+	Decr(key *Key, delta int64, ttl time.Duration) (int64, error)
 	Count() int
 	Close()
 }
@@ -20,7 +39,11 @@ type IStructCache interface {
 type IStructCacheDebug interface {
 	IFlushable
 	Count() int
+	Sets() []SetInfo
+	FlushSet(setName string) int
 	Find(maskedKey string, limit int) []string
+	FindKeys(options FindOptions) ([]FoundKey, string, error)
+	RemoveMatching(options FindOptions) (int, error)
 }
 
 type IStructCacheLogger interface {
@@ -33,4 +56,5 @@ type IStructCacheLogger interface {
 
 type ILimitSetter interface {
 	SetLimit(int)
+	SetSetLimit(setName string, limit int) error
 }
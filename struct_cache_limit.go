@@ -0,0 +1,103 @@
+package cache
+
+import "go-cache/errors"
+
+var _ ILimitSetter = &StructCache{} // StructCache implements ILimitSetter
+
+// SetSetLimit changes limit of entries of registered set. Entries over new limit are evicted in background,
+// puts respect new limit at once. Limit lower than number of shards keeps up to one entry per shard
+func (cache *StructCache) SetSetLimit(setName string, limit int) error {
+	if limit <= 0 {
+		return errors.Errorf("struct_cache: limit of set %q should be positive, got %d", setName, limit)
+	}
+
+	set, ok := cache.getCacheSet(&Key{Set: setName})
+	if !ok {
+		return ErrSetNotFound
+	}
+
+	if cache.logger.IsDebugEnabled() {
+		cache.logger.Debugf("struct_cache: limit of set %q has changed, new value: %d", setName, limit)
+	}
+
+	set.setLimit(limit)
+
+	return nil
+}
+
+// ApplyLimitToSets makes SetLimit change limit of all registered sets, not only of sets created later
+func (cache *StructCache) ApplyLimitToSets(enabled bool) {
+	cache.setsLock.Lock()
+	cache.limitAppliesToSets = enabled
+	cache.setsLock.Unlock()
+}
+
+// limit returns limit of entries of the set
+func (set *cacheSet) limit() int {
+	limit := 0
+	for _, shard := range set.shards {
+		shard.keysLock.RLock()
+		limit += shard.keysLimit
+		shard.keysLock.RUnlock()
+	}
+
+	return limit
+}
+
+// setLimit spreads new limit across shards and starts trimming of shards over it.
+// Evictors are rebuilt for new limit, so policies sized by limit work like after flush
+func (set *cacheSet) setLimit(limit int) {
+	for i, shardLimit := range spreadLimit(limit, len(set.shards)) {
+		shard := set.shards[i]
+
+		shard.keysLock.Lock()
+		if shard.keysLimit != shardLimit {
+			shard.keysLimit = shardLimit
+			shard.rebuildEvictor()
+		}
+		shard.keysLock.Unlock()
+	}
+
+	go set.trimToLimit()
+}
+
+// trimToLimit evicts entries over limit from all shards and reports item count
+func (set *cacheSet) trimToLimit() {
+	for _, shard := range set.shards {
+		shard.keysLock.Lock()
+		trimmed := shard.trim(0, 0, nil)
+		shard.keysLock.Unlock()
+
+		set.evicted(trimmed, EvictCapacity)
+	}
+
+	set.metric.SetItemCount(set.name, set.len())
+}
+
+// rebuildEvictor replaces evictor of shard by new one created for current limit.
+// Entries are moved in eviction order, so the next victim stays the first one to go. Must be called under keysLock
+func (shard *cacheShard) rebuildEvictor() {
+	order := make([]*Entry, 0, len(shard.elements))
+	moved := make(map[*Entry]struct{}, len(shard.elements))
+	for range shard.elements {
+		victim := shard.evictor.Victim()
+		if victim == nil {
+			break
+		}
+		shard.evictor.Remove(victim)
+		order = append(order, victim)
+		moved[victim] = struct{}{}
+	}
+
+	// entries not returned by old evictor are kept as the most recent ones
+	for _, entry := range shard.elements {
+		if _, ok := moved[entry]; !ok {
+			order = append(order, entry)
+		}
+	}
+
+	shard.evictor = shard.policy(shard.keysLimit)
+	for _, entry := range order {
+		shard.evictor.Add(entry)
+	}
+}
@@ -0,0 +1,115 @@
+package cache
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"go-cache/metric/dummy"
+)
+
+// waitCount waits until background trim brings set to expected count
+func waitCount(t *testing.T, structCache *StructCache, setName string, expected int) {
+	set, _ := structCache.getCacheSet(&Key{Set: setName})
+
+	for deadline := time.Now().Add(time.Second); set.len() != expected; {
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected %d entries in set %q, got %d", expected, setName, set.len())
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestStructCache_SetSetLimit(t *testing.T) {
+	structCache := NewStructCacheObject(100, nil, dummy.NewMetric())
+	structCache.RegisterShardedCacheSet("set1", 100, 4, nil)
+	structCache.RegisterCacheSet("set2", 100, nil)
+
+	for i := 0; i < 100; i++ {
+		structCache.Put(i, &Key{Set: "set1", Pk: strconv.Itoa(i)}, time.Minute)
+		structCache.Put(i, &Key{Set: "set2", Pk: strconv.Itoa(i)}, time.Minute)
+	}
+
+	if err := structCache.SetSetLimit("set1", 10); err != nil {
+		t.Fatal(err)
+	}
+	waitCount(t, structCache, "set1", 10)
+
+	if sets := structCache.Sets(); sets[0].Limit != 10 || sets[1].Count != 100 {
+		t.Errorf("Only set1 should be shrunk, got %v", sets)
+	}
+
+	for i := 100; i < 200; i++ {
+		structCache.Put(i, &Key{Set: "set1", Pk: strconv.Itoa(i)}, time.Minute)
+	}
+	if count := structCache.Sets()[0].Count; count != 10 {
+		t.Errorf("Puts should respect new limit, got %d entries", count)
+	}
+
+	if err := structCache.SetSetLimit("missing", 10); err != ErrSetNotFound {
+		t.Errorf("Expected ErrSetNotFound, got %v", err)
+	}
+	if err := structCache.SetSetLimit("set1", 0); err == nil {
+		t.Error("Zero limit should be rejected")
+	}
+}
+
+func TestStructCache_SetLimit_ApplyToSets(t *testing.T) {
+	structCache := NewStructCacheObject(100, nil, dummy.NewMetric())
+	structCache.RegisterCacheSet("set1", 100, nil)
+
+	for i := 0; i < 50; i++ {
+		structCache.Put(i, &Key{Set: "set1", Pk: strconv.Itoa(i)}, time.Minute)
+	}
+
+	structCache.SetLimit(20)
+	if limit := structCache.Sets()[0].Limit; limit != 100 {
+		t.Errorf("SetLimit should not change registered sets by default, got %d", limit)
+	}
+
+	structCache.ApplyLimitToSets(true)
+	structCache.SetLimit(20)
+	waitCount(t, structCache, "set1", 20)
+
+	structCache.Put("data", &Key{Set: "set2", Pk: "1"}, time.Minute)
+	for _, set := range structCache.Sets() {
+		if set.Limit != 20 {
+			t.Errorf("Set %q should have limit 20, got %d", set.Name, set.Limit)
+		}
+	}
+}
+
+func TestStructCache_SetSetLimit_RebuildEvictor(t *testing.T) {
+	structCache := NewStructCacheObject(100, nil, dummy.NewMetric())
+	structCache.RegisterCacheSetWithPolicy("tiny", 10, EvictionTinyLFU, nil)
+	structCache.RegisterCacheSetWithPolicy("lru", 10, EvictionLRU, nil)
+
+	if err := structCache.SetSetLimit("tiny", 1000); err != nil {
+		t.Fatal(err)
+	}
+
+	set, _ := structCache.getCacheSet(&Key{Set: "tiny"})
+	evictor := set.shards[0].evictor.(*tinyLFUEvictor)
+	expected := newTinyLFUEvictor(1000)
+	if evictor.windowLimit != expected.windowLimit || evictor.protectedLimit != expected.protectedLimit ||
+		len(evictor.sketch.rows[0]) != len(expected.sketch.rows[0]) {
+		t.Errorf("Evictor is not sized by new limit: %d, %d, %d",
+			evictor.windowLimit, evictor.protectedLimit, len(evictor.sketch.rows[0]))
+	}
+
+	for i := 0; i < 10; i++ {
+		structCache.Put(i, &Key{Set: "lru", Pk: strconv.Itoa(i)}, time.Minute)
+	}
+	structCache.Get(&Key{Set: "lru", Pk: "0"})
+
+	if err := structCache.SetSetLimit("lru", 5); err != nil {
+		t.Fatal(err)
+	}
+	waitCount(t, structCache, "lru", 5)
+
+	for _, pk := range []string{"0", "7", "8", "9"} {
+		if _, find := structCache.Get(&Key{Set: "lru", Pk: pk}); !find {
+			t.Errorf("Recently used entry %s should be kept", pk)
+		}
+	}
+}
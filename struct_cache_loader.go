@@ -0,0 +1,156 @@
+package cache
+
+import (
+	"context"
+	"time"
+
+	"go-cache/errors"
+	"go-cache/metric"
+)
+
+// loadCall is in-flight loader call shared by concurrent misses of the same key
+type loadCall struct {
+	done chan struct{}
+	data interface{}
+	err  error
+}
+
+// GetOrLoad returns value by key, on miss it calls loader and puts its result into cache with given ttl.
+// Concurrent misses of the same key share one loader call. Loader error is returned to every waiter and isn't cached
+func (cache *StructCache) GetOrLoad(key *Key, ttl time.Duration, loader func() (interface{}, error)) (interface{}, error) {
+	return cache.getOrLoad(context.Background(), key, ttl, 0, loader)
+}
+
+// GetOrLoadCtx works as GetOrLoad but stops waiting for loader when context is done.
+// Loader keeps running in background and its result is cached for other callers
+func (cache *StructCache) GetOrLoadCtx(ctx context.Context, key *Key, ttl time.Duration, loader func() (interface{}, error)) (interface{}, error) {
+	return cache.getOrLoad(ctx, key, ttl, 0, loader)
+}
+
+// GetOrLoadWithErrorTTL works as GetOrLoad but caches loader error as negative entry for errorTTL.
+// Until it expires the error is returned without calling loader, loader may return ErrNotFound to cache absence of value
+func (cache *StructCache) GetOrLoadWithErrorTTL(key *Key, ttl, errorTTL time.Duration, loader func() (interface{}, error)) (interface{}, error) {
+	return cache.getOrLoad(context.Background(), key, ttl, errorTTL, loader)
+}
+
+func (cache *StructCache) getOrLoad(ctx context.Context, key *Key, ttl, errorTTL time.Duration, loader func() (interface{}, error)) (interface{}, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	switch view, result := cache.get(key); result {
+	case LookupHit:
+		return view.data, nil
+	case LookupAbsent:
+		return nil, view.err
+	}
+
+	if errorTTL > 0 {
+		load := loader
+		loader = func() (interface{}, error) {
+			data, err := load()
+			if err != nil {
+				if putErr := cache.putNegative(key, err, errorTTL); putErr != nil {
+					cache.logger.Warningf("struct_cache: could not put loader error %v: %s", key, putErr)
+				}
+			}
+			return data, err
+		}
+	}
+
+	id := key.ID()
+
+	cache.loadsLock.Lock()
+	call, ok := cache.loads[id]
+	if !ok {
+		call = &loadCall{done: make(chan struct{})}
+		cache.loads[id] = call
+
+		go cache.load(call, id, key, loader, func(data interface{}) error {
+			return cache.Put(data, key, ttl)
+		})
+	}
+	cache.loadsLock.Unlock()
+
+	select {
+	case <-call.done:
+		return call.data, call.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// RegisterLoader registers loader used to refresh stale entries of the set in background
+func (cache *StructCache) RegisterLoader(setName string, loader func(key *Key) (interface{}, error)) {
+	cache.setsLock.Lock()
+	cache.loaders[setName] = loader
+	cache.setsLock.Unlock()
+}
+
+// refresh starts background reload of stale entry unless the key is being loaded already.
+// Key is stored key of entry, so refreshed entry keeps its tags
+func (cache *StructCache) refresh(refreshKey Key, softTTL, ttl time.Duration) {
+	cache.setsLock.RLock()
+	loader, ok := cache.loaders[refreshKey.Set]
+	cache.setsLock.RUnlock()
+	if !ok {
+		return
+	}
+
+	id := refreshKey.ID()
+
+	cache.loadsLock.Lock()
+	if _, ok := cache.loads[id]; ok {
+		cache.loadsLock.Unlock()
+		return
+	}
+
+	call := &loadCall{done: make(chan struct{})}
+	cache.loads[id] = call
+	cache.loadsLock.Unlock()
+
+	if cache.logger.IsDebugEnabled() {
+		cache.logger.Debugf("struct_cache: REFRESH stale %v", &refreshKey)
+	}
+
+	go cache.load(call, id, &refreshKey, func() (interface{}, error) {
+		return loader(&refreshKey)
+	}, func(data interface{}) error {
+		return cache.put(data, &refreshKey, softTTL, ttl)
+	})
+}
+
+func (cache *StructCache) load(call *loadCall, id string, key *Key, loader func() (interface{}, error), put func(data interface{}) error) {
+	ts := time.Now()
+
+	defer func() {
+		if r := recover(); r != nil {
+			call.data, call.err = nil, errors.Errorf("struct_cache: loader panic for %v: %v", key, r)
+		}
+
+		set, _ := cache.getCacheSet(key)
+		cache.metric.ObserveRT(set.labels(map[string]string{
+			metric.LabelSet:       key.Set,
+			metric.LabelOperation: "load",
+			metric.LabelIsError:   metric.IsError(call.err),
+		}), metric.SinceMs(ts))
+
+		cache.loadsLock.Lock()
+		delete(cache.loads, id)
+		cache.loadsLock.Unlock()
+
+		close(call.done)
+	}()
+
+	call.data, call.err = loader()
+	if call.err != nil {
+		if cache.logger.IsDebugEnabled() {
+			cache.logger.Debugf("struct_cache: LOAD %v failed: %s", key, call.err)
+		}
+		return
+	}
+
+	if err := put(call.data); err != nil {
+		cache.logger.Warningf("struct_cache: could not put loaded value %v: %s", key, err)
+	}
+}
@@ -0,0 +1,237 @@
+package cache
+
+import (
+	"context"
+	"go-cache/errors"
+	"go-cache/metric/dummy"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestStructCache_GetOrLoad_Ok(t *testing.T) {
+	structCache := NewStructCacheObject(8000, nil, dummy.NewMetric())
+	k := &Key{
+		Set: "set1",
+		Pk:  "1",
+	}
+
+	data, err := structCache.GetOrLoad(k, time.Minute*5, func() (interface{}, error) {
+		return "data", nil
+	})
+	if err != nil || data != "data" {
+		t.Errorf("Data is not expected: %v, %v", data, err)
+	}
+
+	if cached, find := structCache.Get(k); !find || cached != "data" {
+		t.Error("Loaded data should be cached")
+	}
+}
+
+func TestStructCache_GetOrLoad_Coalescing(t *testing.T) {
+	structCache := NewStructCacheObject(8000, nil, dummy.NewMetric())
+	k := &Key{
+		Set: "set1",
+		Pk:  "1",
+	}
+
+	var (
+		calls   int32
+		wg      sync.WaitGroup
+		release = make(chan struct{})
+	)
+
+	loader := func() (interface{}, error) {
+		atomic.AddInt32(&calls, 1)
+		<-release
+		return "data", nil
+	}
+
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if data, err := structCache.GetOrLoad(k, time.Minute*5, loader); err != nil || data != "data" {
+				t.Errorf("Data is not expected: %v, %v", data, err)
+			}
+		}()
+	}
+
+	time.Sleep(time.Millisecond * 20)
+	close(release)
+	wg.Wait()
+
+	if calls != 1 {
+		t.Errorf("Loader should be called once, called: %d", calls)
+	}
+}
+
+func TestStructCache_GetOrLoad_ErrorNotCached(t *testing.T) {
+	structCache := NewStructCacheObject(8000, nil, dummy.NewMetric())
+	k := &Key{
+		Set: "set1",
+		Pk:  "1",
+	}
+	loadErr := errors.New("database is down")
+
+	_, err := structCache.GetOrLoad(k, time.Minute*5, func() (interface{}, error) {
+		return nil, loadErr
+	})
+	if err != loadErr {
+		t.Errorf("Loader error should be returned, got: %v", err)
+	}
+
+	if _, find := structCache.Get(k); find {
+		t.Error("Error shouldn't be cached")
+	}
+
+	data, err := structCache.GetOrLoad(k, time.Minute*5, func() (interface{}, error) {
+		return "data", nil
+	})
+	if err != nil || data != "data" {
+		t.Errorf("Data is not expected: %v, %v", data, err)
+	}
+}
+
+func TestStructCache_SoftTTL_StaleWhileRevalidate(t *testing.T) {
+	structCache := NewStructCacheObject(8000, nil, dummy.NewMetric())
+	k := &Key{
+		Set: "set1",
+		Pk:  "1",
+	}
+
+	refreshed := make(chan struct{})
+	structCache.RegisterLoader("set1", func(key *Key) (interface{}, error) {
+		defer close(refreshed)
+		return "fresh", nil
+	})
+
+	if err := structCache.PutWithSoftTTL("stale", k, time.Minute, time.Hour); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	data, stale, find := structCache.GetWithStale(k)
+	if !find || stale || data != "stale" {
+		t.Errorf("Fresh data is not expected: %v, %t, %t", data, stale, find)
+	}
+
+	set, _ := structCache.getCacheSet(k)
+	set.shard(k.Pk).elements[k.Pk].SoftExpires = time.Now().Add(-time.Millisecond)
+
+	data, stale, find = structCache.GetWithStale(k)
+	if !find || !stale || data != "stale" {
+		t.Errorf("Stale data is not expected: %v, %t, %t", data, stale, find)
+	}
+
+	select {
+	case <-refreshed:
+	case <-time.After(time.Second):
+		t.Fatal("Stale entry was not refreshed")
+	}
+
+	// wait for refreshed value to be put
+	for i := 0; i < 100; i++ {
+		if data, stale, _ = structCache.GetWithStale(k); data == "fresh" {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	if data != "fresh" || stale {
+		t.Errorf("Refreshed data is not expected: %v, %t", data, stale)
+	}
+}
+
+func TestStructCache_SoftTTL_RefreshKeepsTags(t *testing.T) {
+	structCache := NewStructCacheObject(8000, nil, dummy.NewMetric())
+	k := &Key{Set: "set1", Pk: "1", Tags: []string{"t"}}
+
+	var loadedKey *Key
+	refreshed := make(chan struct{})
+	structCache.RegisterLoader("set1", func(key *Key) (interface{}, error) {
+		defer close(refreshed)
+		loadedKey = key
+		return "fresh", nil
+	})
+
+	if err := structCache.PutWithSoftTTL("stale", k, time.Minute, time.Hour); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	set, _ := structCache.getCacheSet(k)
+	set.shard(k.Pk).elements[k.Pk].SoftExpires = time.Now().Add(-time.Millisecond)
+
+	structCache.GetWithStale(&Key{Set: "set1", Pk: "1"})
+
+	select {
+	case <-refreshed:
+	case <-time.After(time.Second):
+		t.Fatal("Stale entry was not refreshed")
+	}
+
+	// wait for refreshed value to be put
+	for i := 0; i < 100; i++ {
+		if data, _, _ := structCache.GetWithStale(k); data == "fresh" {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	if len(loadedKey.Tags) != 1 || loadedKey.Tags[0] != "t" {
+		t.Errorf("Loader should get stored key with tags, got %v", loadedKey)
+	}
+
+	structCache.Remove(&Key{Set: "set1", Tags: []string{"t"}})
+	if data, find := structCache.Get(k); find {
+		t.Errorf("Refreshed entry should be removed by tag, got %v", data)
+	}
+}
+
+func TestStructCache_PutWithSoftTTL_Invalid(t *testing.T) {
+	structCache := NewStructCacheObject(8000, nil, dummy.NewMetric())
+	k := &Key{
+		Set: "set1",
+		Pk:  "1",
+	}
+
+	if err := structCache.PutWithSoftTTL("data", k, time.Hour, time.Minute); err == nil {
+		t.Error("Soft TTL greater than TTL should be rejected")
+	}
+}
+
+func TestStructCache_GetOrLoadCtx_Canceled(t *testing.T) {
+	structCache := NewStructCacheObject(8000, nil, dummy.NewMetric())
+	k := &Key{
+		Set: "set1",
+		Pk:  "1",
+	}
+
+	release := make(chan struct{})
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	_, err := structCache.GetOrLoadCtx(ctx, k, time.Minute, func() (interface{}, error) {
+		<-release
+		return "data", nil
+	})
+	if err != context.DeadlineExceeded {
+		t.Fatalf("Expected deadline error, got %v", err)
+	}
+
+	// loader keeps running and its result is cached for next callers
+	close(release)
+	data, err := structCache.GetOrLoad(k, time.Minute, func() (interface{}, error) {
+		return "other", nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data != "data" && data != "other" {
+		t.Errorf("Unexpected data %v", data)
+	}
+
+	if _, err := structCache.GetOrLoadCtx(ctx, k, time.Minute, nil); err == nil {
+		t.Error("Done context should be rejected")
+	}
+}
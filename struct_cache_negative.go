@@ -0,0 +1,35 @@
+package cache
+
+import "time"
+
+// negativeValue is stored instead of data of key known to be absent
+type negativeValue struct {
+	err error
+}
+
+// PutNegative puts negative entry, the key is known to be absent for ttl.
+// Get reports it as miss, Lookup reports it as LookupAbsent
+func (cache *StructCache) PutNegative(key *Key, ttl time.Duration) error {
+	return cache.putNegative(key, ErrNotFound, ttl)
+}
+
+// Lookup returns value by key and tells whether it is hit, miss or known absence
+func (cache *StructCache) Lookup(key *Key) (interface{}, LookupResult) {
+	view, result := cache.get(key)
+
+	return view.data, result
+}
+
+// putNegative puts negative entry keeping the error returned for the key
+func (cache *StructCache) putNegative(key *Key, err error, ttl time.Duration) error {
+	return cache.put(negativeValue{err: err}, key, 0, ttl)
+}
+
+// publicValue returns value of entry shown to callers, negative entries have no value
+func publicValue(data interface{}) interface{} {
+	if _, isNegative := data.(negativeValue); isNegative {
+		return nil
+	}
+
+	return data
+}
@@ -0,0 +1,142 @@
+package cache
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go-cache/errors"
+	"go-cache/metric/dummy"
+)
+
+type negativeMetric struct {
+	dummy.Metric
+	hits, misses, negativeHits int32
+}
+
+func (m *negativeMetric) RegisterHit(labels map[string]string) {
+	atomic.AddInt32(&m.hits, 1)
+}
+
+func (m *negativeMetric) RegisterMiss(labels map[string]string) {
+	atomic.AddInt32(&m.misses, 1)
+}
+
+func (m *negativeMetric) RegisterNegativeHit(labels map[string]string) {
+	atomic.AddInt32(&m.negativeHits, 1)
+}
+
+func TestStructCache_PutNegative(t *testing.T) {
+	m := &negativeMetric{}
+	structCache := NewStructCacheObject(8000, nil, m)
+	k := &Key{Set: "set1", Pk: "1"}
+
+	if _, result := structCache.Lookup(k); result != LookupMiss {
+		t.Errorf("Expected miss, got %s", result)
+	}
+
+	if err := structCache.PutNegative(k, time.Minute); err != nil {
+		t.Fatal(err)
+	}
+
+	if data, result := structCache.Lookup(k); result != LookupAbsent || data != nil {
+		t.Errorf("Expected absent, got %s, %v", result, data)
+	}
+	if _, ok := structCache.Get(k); ok {
+		t.Error("Negative entry should not be returned by Get")
+	}
+
+	structCache.Put("data", k, time.Minute)
+	if data, result := structCache.Lookup(k); result != LookupHit || data != "data" {
+		t.Errorf("Expected hit, got %s, %v", result, data)
+	}
+
+	if m.hits != 1 || m.misses != 1 || m.negativeHits != 2 {
+		t.Errorf("Unexpected metrics: hits %d, misses %d, negative hits %d", m.hits, m.misses, m.negativeHits)
+	}
+}
+
+func TestStructCache_PutNegative_StrictSet(t *testing.T) {
+	structCache := NewStructCacheObject(8000, nil, dummy.NewMetric())
+	structCache.RegisterCacheSetWithOptions("strict", CacheSetOptions{Limit: 10, ValueSafety: ValueSafetyStrict})
+
+	if err := structCache.PutNegative(&Key{Set: "strict", Pk: "1"}, time.Minute); err != nil {
+		t.Errorf("Negative entry should be accepted by strict set: %s", err)
+	}
+}
+
+func TestStructCache_GetOrLoadWithErrorTTL(t *testing.T) {
+	clock := NewFakeClock(time.Now())
+	structCache := NewStructCacheObject(8000, nil, dummy.NewMetric())
+	structCache.SetClock(clock)
+	k := &Key{Set: "set1", Pk: "1"}
+
+	calls := 0
+	loadErr := errors.New("database is down")
+	loader := func() (interface{}, error) {
+		calls++
+		if calls == 1 {
+			return nil, loadErr
+		}
+		return "data", nil
+	}
+
+	for i := 0; i < 3; i++ {
+		if _, err := structCache.GetOrLoadWithErrorTTL(k, time.Hour, time.Second, loader); err != loadErr {
+			t.Errorf("Expected cached loader error, got %v", err)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("Loader should be called once while error is cached, called %d times", calls)
+	}
+
+	clock.Advance(time.Second)
+
+	data, err := structCache.GetOrLoadWithErrorTTL(k, time.Hour, time.Second, loader)
+	if err != nil || data != "data" {
+		t.Errorf("Unexpected result %v, %v", data, err)
+	}
+}
+
+func TestStructCache_GetOrLoadWithErrorTTL_NotFound(t *testing.T) {
+	structCache := NewStructCacheObject(8000, nil, dummy.NewMetric())
+	k := &Key{Set: "set1", Pk: "1"}
+
+	_, err := structCache.GetOrLoadWithErrorTTL(k, time.Hour, time.Minute, func() (interface{}, error) {
+		return nil, ErrNotFound
+	})
+	if err != ErrNotFound {
+		t.Fatalf("Expected ErrNotFound, got %v", err)
+	}
+
+	if _, result := structCache.Lookup(k); result != LookupAbsent {
+		t.Errorf("Expected absent, got %s", result)
+	}
+}
+
+func TestStructCache_PutNegative_Internals(t *testing.T) {
+	structCache := NewStructCacheObject(8000, nil, dummy.NewMetric())
+	structCache.Put("data", &Key{Set: "set1", Pk: "1"}, time.Minute)
+	structCache.PutNegative(&Key{Set: "set1", Pk: "2"}, time.Minute)
+
+	found, _, err := structCache.FindKeys(FindOptions{Set: "set1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(found) != 1 || found[0].Pk != "1" {
+		t.Errorf("Keys known to be absent should be skipped, got %v", found)
+	}
+
+	values := make(map[string]interface{})
+	structCache.OnEvict(func(key *Key, value interface{}, reason EvictReason) {
+		values[key.Pk] = value
+	})
+	structCache.Flush()
+
+	if value, ok := values["2"]; !ok || value != nil {
+		t.Errorf("Negative entry should be passed to callback with nil value, got %#v", value)
+	}
+	if values["1"] != "data" {
+		t.Errorf("Unexpected value %#v", values["1"])
+	}
+}
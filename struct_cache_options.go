@@ -0,0 +1,81 @@
+package cache
+
+import "time"
+
+// DefaultCollectorInterval is interval of expired entries collecting in sets created implicitly by Put
+const DefaultCollectorInterval = 5 * time.Minute
+
+// CacheSetOptions contains parameters of cache set
+type CacheSetOptions struct {
+	// Limit is max number of entries
+	Limit int
+	// BytesLimit is max approximate size of values in bytes, 0 means set isn't limited by size
+	BytesLimit int64
+	// Shards is number of independently locked parts of set, 1 by default
+	Shards int
+
+	// DefaultTTL is used by Put called with zero TTL
+	DefaultTTL time.Duration
+	// MaxTTL cuts longer TTLs, 0 means TTL isn't limited
+	MaxTTL time.Duration
+
+	// CollectorInterval is interval of expired entries collecting, 0 means expired entries are removed on Get only
+	CollectorInterval time.Duration
+	// EvictionPolicy chooses entries evicted from full set, LRU by default
+	EvictionPolicy EvictionPolicy
+
+	// MetricLabels are added to metrics of the set
+	MetricLabels map[string]string
+
+	// ValueSafety protects stored values from being changed by callers, off by default
+	ValueSafety ValueSafety
+}
+
+// SetDefaultCacheSetOptions changes options of sets which will be created implicitly by Put.
+// Limit of such sets is taken from SetLimit
+func (cache *StructCache) SetDefaultCacheSetOptions(options CacheSetOptions) {
+	cache.setsLock.Lock()
+	cache.defaultOptions = options
+	cache.setsLock.Unlock()
+}
+
+func (cache *StructCache) implicitSetOptions() CacheSetOptions {
+	cache.setsLock.RLock()
+	options := cache.defaultOptions
+	options.Limit = cache.defaultLimit
+	cache.setsLock.RUnlock()
+
+	return options
+}
+
+// resolveTTL applies default and max TTL of the set
+func (set *cacheSet) resolveTTL(softTTL, ttl time.Duration) (time.Duration, time.Duration) {
+	if ttl <= 0 {
+		ttl = set.defaultTTL
+	}
+
+	if set.maxTTL > 0 && ttl > set.maxTTL {
+		ttl = set.maxTTL
+	}
+
+	if softTTL >= ttl {
+		softTTL = 0
+	}
+
+	return softTTL, ttl
+}
+
+// labels adds labels of the set to metric labels, set may be nil
+func (set *cacheSet) labels(labels map[string]string) map[string]string {
+	if set == nil {
+		return labels
+	}
+
+	for name, value := range set.metricLabels {
+		if _, ok := labels[name]; !ok {
+			labels[name] = value
+		}
+	}
+
+	return labels
+}
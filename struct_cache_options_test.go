@@ -0,0 +1,80 @@
+package cache
+
+import (
+	"testing"
+	"time"
+
+	"go-cache/metric/dummy"
+)
+
+func TestStructCache_RegisterCacheSetWithOptions_TTL(t *testing.T) {
+	structCache := NewStructCacheObject(100, nil, dummy.NewMetric())
+	err := structCache.RegisterCacheSetWithOptions("set1", CacheSetOptions{
+		Limit:      10,
+		DefaultTTL: time.Minute,
+		MaxTTL:     time.Hour,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	k1 := &Key{Set: "set1", Pk: "1"}
+	if err := structCache.Put("data", k1, 0); err != nil {
+		t.Fatalf("Put with zero TTL should use default TTL: %s", err)
+	}
+
+	k2 := &Key{Set: "set1", Pk: "2"}
+	if err := structCache.Put("data", k2, 24*time.Hour); err != nil {
+		t.Fatal(err)
+	}
+
+	set, _ := structCache.getCacheSet(k1)
+	if ttl := set.shard(k1.Pk).elements[k1.Pk].ttl; ttl != time.Minute {
+		t.Errorf("Expected default TTL %s, got %s", time.Minute, ttl)
+	}
+	if ttl := set.shard(k2.Pk).elements[k2.Pk].ttl; ttl != time.Hour {
+		t.Errorf("Expected TTL cut to %s, got %s", time.Hour, ttl)
+	}
+
+	if err := structCache.Put("data", &Key{Set: "set2", Pk: "1"}, 0); err == nil {
+		t.Error("Put with zero TTL into set without default TTL should fail")
+	}
+}
+
+func TestStructCache_RegisterCacheSetWithOptions_Collector(t *testing.T) {
+	structCache := NewStructCacheObject(100, nil, dummy.NewMetric())
+	err := structCache.RegisterCacheSetWithOptions("set1", CacheSetOptions{
+		Limit:             10,
+		CollectorInterval: time.Millisecond,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := structCache.RegisterCacheSetWithOptions("set1", CacheSetOptions{Limit: 10}); err != ErrSetAlreadyExists {
+		t.Errorf("Expected ErrSetAlreadyExists, got %v", err)
+	}
+
+	structCache.Put("data", &Key{Set: "set1", Pk: "1"}, 10*time.Millisecond)
+	time.Sleep(50 * time.Millisecond)
+
+	if count := structCache.Count(); count != 0 {
+		t.Errorf("Expired entry should be collected, count %d", count)
+	}
+
+	structCache.Close()
+}
+
+func TestCacheSet_Labels(t *testing.T) {
+	set := &cacheSet{metricLabels: map[string]string{"team": "search", "set": "other"}}
+
+	labels := set.labels(map[string]string{"set": "set1"})
+	if labels["team"] != "search" || labels["set"] != "set1" {
+		t.Errorf("Unexpected labels %v", labels)
+	}
+
+	var nilSet *cacheSet
+	if labels := nilSet.labels(map[string]string{"set": "set1"}); len(labels) != 1 {
+		t.Errorf("Unexpected labels %v", labels)
+	}
+}
@@ -0,0 +1,83 @@
+package cache
+
+import (
+	"sort"
+	"time"
+)
+
+// SetInfo describes registered cache set
+type SetInfo struct {
+	Name  string
+	Limit int
+	Count int
+	// CollectorInterval is period of expired entries collection,
+	// zero if set has no collector or it was registered with ticker
+	CollectorInterval time.Duration
+}
+
+// Sets returns info of registered sets ordered by name
+func (cache *StructCache) Sets() []SetInfo {
+	sets := cache.findSets("")
+
+	infos := make([]SetInfo, 0, len(sets))
+	for _, set := range sets {
+		infos = append(infos, SetInfo{
+			Name:              set.name,
+			Limit:             set.limit(),
+			Count:             set.len(),
+			CollectorInterval: set.collectorInterval,
+		})
+	}
+
+	sort.Slice(infos, func(i, j int) bool {
+		return infos[i].Name < infos[j].Name
+	})
+
+	return infos
+}
+
+// FlushSet removes all entries of the set and returns number of flushed entries
+func (cache *StructCache) FlushSet(name string) int {
+	if cache.logger.IsDebugEnabled() {
+		cache.logger.Debugf("struct_cache: flush set %q", name)
+	}
+
+	set, ok := cache.getCacheSet(&Key{Set: name})
+	if !ok {
+		return 0
+	}
+
+	count := set.flush()
+	cache.metric.SetItemCount(set.name, set.len())
+
+	return count
+}
+
+// UnregisterCacheSet stops collector of the set and removes the set with all its entries,
+// eviction callbacks get them as flushed. Returns ErrSetNotFound if set is not registered
+func (cache *StructCache) UnregisterCacheSet(name string) error {
+	cache.setsLock.Lock()
+	set, ok := cache.setsCollection[name]
+	if ok {
+		delete(cache.setsCollection, name)
+	}
+	cache.setsLock.Unlock()
+
+	if !ok {
+		return ErrSetNotFound
+	}
+
+	if cache.logger.IsDebugEnabled() {
+		cache.logger.Debugf("struct_cache: unregister set %q", name)
+	}
+
+	if set.ticker != nil {
+		set.quitCollectorChan <- struct{}{}
+		<-set.collectorDone
+	}
+
+	set.flush()
+	cache.metric.SetItemCount(set.name, 0)
+
+	return nil
+}
@@ -0,0 +1,91 @@
+package cache
+
+import (
+	"testing"
+	"time"
+
+	"go-cache/metric/dummy"
+)
+
+func TestStructCache_Sets(t *testing.T) {
+	structCache := NewStructCacheObject(8000, nil, dummy.NewMetric())
+	structCache.RegisterCacheSetWithOptions("b", CacheSetOptions{Limit: 10, CollectorInterval: time.Minute})
+	structCache.RegisterCacheSet("a", 20, nil)
+	structCache.Put("data", &Key{Set: "b", Pk: "1"}, time.Minute)
+	structCache.Put("data", &Key{Set: "b", Pk: "2"}, time.Minute)
+
+	expected := []SetInfo{
+		{Name: "a", Limit: 20},
+		{Name: "b", Limit: 10, Count: 2, CollectorInterval: time.Minute},
+	}
+
+	sets := structCache.Sets()
+	if len(sets) != len(expected) {
+		t.Fatalf("Expected %v, got %v", expected, sets)
+	}
+	for i := range expected {
+		if sets[i] != expected[i] {
+			t.Errorf("Expected %v, got %v", expected[i], sets[i])
+		}
+	}
+}
+
+func TestStructCache_FlushSet(t *testing.T) {
+	structCache := NewStructCacheObject(8000, nil, dummy.NewMetric())
+	k1 := &Key{Set: "set1", Pk: "1"}
+	k2 := &Key{Set: "set2", Pk: "1"}
+	structCache.Put("data", k1, time.Minute)
+	structCache.Put("data", k2, time.Minute)
+
+	if count := structCache.FlushSet("set1"); count != 1 {
+		t.Errorf("Expected 1 flushed entry, got %d", count)
+	}
+	if count := structCache.FlushSet("missing"); count != 0 {
+		t.Errorf("Expected 0 flushed entries, got %d", count)
+	}
+
+	if _, ok := structCache.Get(k1); ok {
+		t.Error("Entry of flushed set should be removed")
+	}
+	if _, ok := structCache.Get(k2); !ok {
+		t.Error("Entry of other set should be kept")
+	}
+}
+
+func TestStructCache_UnregisterCacheSet(t *testing.T) {
+	clock := NewFakeClock(time.Now())
+	structCache := NewStructCacheObject(8000, nil, dummy.NewMetric())
+	structCache.SetClock(clock)
+	structCache.RegisterCacheSetWithOptions("set1", CacheSetOptions{Limit: 10, CollectorInterval: time.Minute})
+	k := &Key{Set: "set1", Pk: "1"}
+	structCache.Put("data", k, time.Minute)
+
+	var evicted []EvictReason
+	structCache.OnEvict(func(key *Key, data interface{}, reason EvictReason) {
+		evicted = append(evicted, reason)
+	})
+
+	if err := structCache.UnregisterCacheSet("set1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := structCache.UnregisterCacheSet("set1"); err != ErrSetNotFound {
+		t.Errorf("Expected ErrSetNotFound, got %v", err)
+	}
+
+	if len(evicted) != 1 || evicted[0] != EvictFlushed {
+		t.Errorf("Entries of unregistered set should be flushed, got %v", evicted)
+	}
+	if len(structCache.Sets()) != 0 {
+		t.Errorf("Set should be unregistered, got %v", structCache.Sets())
+	}
+	if len(clock.tickers) != 0 {
+		t.Errorf("Collector ticker should be stopped, %d tickers left", len(clock.tickers))
+	}
+
+	if err := structCache.RegisterCacheSetWithOptions("set1", CacheSetOptions{Limit: 10}); err != nil {
+		t.Errorf("Set should be registered again, got %v", err)
+	}
+	if _, ok := structCache.Get(k); ok {
+		t.Error("Entry of unregistered set should not be found")
+	}
+}
@@ -0,0 +1,184 @@
+package cache
+
+import (
+	"sync"
+)
+
+// cacheShard is independently locked part of cache set with own eviction order
+type cacheShard struct {
+	elements   map[string]*Entry
+	tags       map[string]map[string]struct{}
+	expiry     expiryHeap
+	evictor    Evictor
+	policy     EvictionPolicy
+	keysLock   sync.RWMutex
+	keysLimit  int
+	bytes      int64
+	bytesLimit int64
+}
+
+func newCacheShard(limit int, bytesLimit int64, policy EvictionPolicy) *cacheShard {
+	return &cacheShard{
+		elements:   make(map[string]*Entry),
+		tags:       make(map[string]map[string]struct{}),
+		evictor:    policy(limit),
+		policy:     policy,
+		keysLimit:  limit,
+		bytesLimit: bytesLimit,
+	}
+}
+
+// shardLimits spreads set limit across shards, the first shards take the remainder
+func shardLimits(limit, shards int) []int {
+	if shards > limit {
+		shards = limit
+	}
+	if shards < 1 {
+		shards = 1
+	}
+
+	return spreadLimit(limit, shards)
+}
+
+// spreadLimit spreads set limit across given number of shards, the first shards take the remainder
+func spreadLimit(limit, shards int) []int {
+	limits := make([]int, shards)
+	for i := range limits {
+		limits[i] = limit / shards
+		if i < limit%shards {
+			limits[i]++
+		}
+	}
+
+	return limits
+}
+
+// shardBytesLimit spreads set byte limit across shards, 0 means set isn't limited by size
+func shardBytesLimit(bytesLimit int64, shards int) int64 {
+	if bytesLimit <= 0 {
+		return 0
+	}
+
+	return (bytesLimit + int64(shards) - 1) / int64(shards)
+}
+
+// shardIndex returns shard number for primary key
+func shardIndex(pk string, shards int) int {
+	if shards == 1 {
+		return 0
+	}
+
+	return int(hashPk(pk) % uint64(shards))
+}
+
+// add inserts new entry into shard, lock must be held by caller
+func (shard *cacheShard) add(entry *Entry) {
+	shard.elements[entry.Key.Pk] = entry
+	shard.bytes += entry.size
+	shard.indexTags(entry)
+	shard.schedule(entry)
+	shard.evictor.Add(entry)
+}
+
+// removeEntry removes entry from shard, lock must be held by caller
+func (shard *cacheShard) removeEntry(entry *Entry) {
+	delete(shard.elements, entry.Key.Pk)
+	shard.bytes -= entry.size
+	shard.unindexTags(entry)
+	shard.unschedule(entry)
+	shard.evictor.Remove(entry)
+}
+
+// retag replaces key of stored entry and updates tags index, lock must be held by caller
+func (shard *cacheShard) retag(entry *Entry, key *Key) {
+	shard.unindexTags(entry)
+	entry.Key = key
+	shard.indexTags(entry)
+}
+
+func (shard *cacheShard) indexTags(entry *Entry) {
+	for _, tag := range entry.Key.Tags {
+		pks, ok := shard.tags[tag]
+		if !ok {
+			pks = make(map[string]struct{})
+			shard.tags[tag] = pks
+		}
+		pks[entry.Key.Pk] = struct{}{}
+	}
+}
+
+func (shard *cacheShard) unindexTags(entry *Entry) {
+	for _, tag := range entry.Key.Tags {
+		if pks, ok := shard.tags[tag]; ok {
+			delete(pks, entry.Key.Pk)
+			if len(pks) == 0 {
+				delete(shard.tags, tag)
+			}
+		}
+	}
+}
+
+// removeByTag removes all entries having given tag. Returns removed entries
+func (shard *cacheShard) removeByTag(tag string) []*Entry {
+	var removed []*Entry
+
+	shard.keysLock.Lock()
+	for pk := range shard.tags[tag] {
+		if entry, ok := shard.elements[pk]; ok {
+			shard.removeEntry(entry)
+			removed = append(removed, entry)
+		}
+	}
+	shard.keysLock.Unlock()
+
+	return removed
+}
+
+// resize updates size of stored entry, lock must be held by caller. Returns size difference
+func (shard *cacheShard) resize(entry *Entry, size int64) int64 {
+	delta := size - entry.size
+	entry.size = size
+	shard.bytes += delta
+
+	return delta
+}
+
+// trim removes entries chosen by eviction policy until shard has room for 'slots' more entries
+// and 'incoming' more bytes, keep entry is never removed. Lock must be held by caller.
+// Returns removed entries
+func (shard *cacheShard) trim(slots int, incoming int64, keep *Entry) []*Entry {
+	var removed []*Entry
+
+	for len(shard.elements) > 0 && shard.overLimit(slots, incoming) {
+		victim := shard.evictor.Victim()
+		if victim == nil || victim == keep {
+			break
+		}
+		shard.removeEntry(victim)
+		removed = append(removed, victim)
+	}
+
+	return removed
+}
+
+func (shard *cacheShard) overLimit(slots int, incoming int64) bool {
+	if len(shard.elements)+slots > shard.keysLimit {
+		return true
+	}
+
+	return shard.bytesLimit > 0 && shard.bytes+incoming > shard.bytesLimit
+}
+
+// flush removes all entries from shard and returns removed entries and their size
+func (shard *cacheShard) flush() (map[string]*Entry, int64) {
+	shard.keysLock.Lock()
+	elements, bytes := shard.elements, shard.bytes
+	shard.elements = make(map[string]*Entry)
+	shard.tags = make(map[string]map[string]struct{})
+	shard.expiry = nil
+	shard.bytes = 0
+	shard.evictor = shard.policy(shard.keysLimit)
+	shard.keysLock.Unlock()
+
+	return elements, bytes
+}
@@ -0,0 +1,106 @@
+package cache
+
+import "reflect"
+
+// Sizer is implemented by values which know their approximate size in bytes.
+// Values that don't implement it are measured by reflection
+type Sizer interface {
+	Size() int
+}
+
+// estimateSize returns approximate memory footprint of data in bytes
+func estimateSize(data interface{}) int64 {
+	if sizer, ok := data.(Sizer); ok {
+		return int64(sizer.Size())
+	}
+
+	return estimateValueSize(reflect.ValueOf(data), make(map[uintptr]struct{}))
+}
+
+func estimateValueSize(v reflect.Value, seen map[uintptr]struct{}) int64 {
+	if !v.IsValid() {
+		return 0
+	}
+
+	return int64(v.Type().Size()) + estimateIndirectSize(v, seen)
+}
+
+// estimateIndirectSize returns size of memory referenced by value, seen protects from cycles and double counting
+func estimateIndirectSize(v reflect.Value, seen map[uintptr]struct{}) int64 {
+	switch v.Kind() {
+	case reflect.String:
+		return int64(v.Len())
+
+	case reflect.Ptr:
+		if v.IsNil() || isSeen(v.Pointer(), seen) {
+			return 0
+		}
+		return estimateValueSize(v.Elem(), seen)
+
+	case reflect.Interface:
+		if v.IsNil() {
+			return 0
+		}
+		return estimateValueSize(v.Elem(), seen)
+
+	case reflect.Slice:
+		if v.IsNil() || isSeen(v.Pointer(), seen) {
+			return 0
+		}
+		size := int64(v.Cap()) * int64(v.Type().Elem().Size())
+		for i := 0; i < v.Len() && hasIndirect(v.Type().Elem()); i++ {
+			size += estimateIndirectSize(v.Index(i), seen)
+		}
+		return size
+
+	case reflect.Array:
+		var size int64
+		for i := 0; i < v.Len() && hasIndirect(v.Type().Elem()); i++ {
+			size += estimateIndirectSize(v.Index(i), seen)
+		}
+		return size
+
+	case reflect.Map:
+		if v.IsNil() || isSeen(v.Pointer(), seen) {
+			return 0
+		}
+		var size int64
+		iter := v.MapRange()
+		for iter.Next() {
+			size += estimateValueSize(iter.Key(), seen) + estimateValueSize(iter.Value(), seen)
+		}
+		return size
+
+	case reflect.Struct:
+		var size int64
+		for i := 0; i < v.NumField(); i++ {
+			size += estimateIndirectSize(v.Field(i), seen)
+		}
+		return size
+	}
+
+	return 0
+}
+
+func isSeen(ptr uintptr, seen map[uintptr]struct{}) bool {
+	if _, ok := seen[ptr]; ok {
+		return true
+	}
+	seen[ptr] = struct{}{}
+
+	return false
+}
+
+// hasIndirect reports whether values of type may reference additional memory
+func hasIndirect(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64, reflect.Complex64, reflect.Complex128:
+		return false
+	case reflect.Array:
+		return hasIndirect(t.Elem())
+	}
+
+	return true
+}
@@ -0,0 +1,128 @@
+package cache
+
+import (
+	"go-cache/metric/dummy"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type sizedTestValue struct {
+	size int
+}
+
+func (v sizedTestValue) Size() int {
+	return v.size
+}
+
+func TestEstimateSize(t *testing.T) {
+	type node struct {
+		Name string
+		Next *node
+	}
+
+	if size := estimateSize(sizedTestValue{size: 42}); size != 42 {
+		t.Errorf("Sizer should be used, got: %d", size)
+	}
+
+	if size := estimateSize(make([]byte, 1000)); size < 1000 {
+		t.Errorf("Slice size is too small: %d", size)
+	}
+
+	if size := estimateSize(strings.Repeat("a", 500)); size < 500 {
+		t.Errorf("String size is too small: %d", size)
+	}
+
+	m := map[string][]byte{"a": make([]byte, 100), "b": make([]byte, 100)}
+	if size := estimateSize(m); size < 200 {
+		t.Errorf("Map size is too small: %d", size)
+	}
+
+	// cyclic structures must not hang estimator
+	a := &node{Name: "a"}
+	a.Next = &node{Name: "b", Next: a}
+	if size := estimateSize(a); size <= 0 {
+		t.Errorf("Size is not expected: %d", size)
+	}
+}
+
+func TestStructCache_ByteLimit_Trim(t *testing.T) {
+	structCache := NewStructCacheObject(8000, nil, dummy.NewMetric())
+	structCache.RegisterCacheSetWithByteLimit("set1", 1000, 1000, nil)
+
+	for i := 0; i < 10; i++ {
+		k := &Key{
+			Set: "set1",
+			Pk:  strconv.Itoa(i),
+		}
+		if err := structCache.Put(sizedTestValue{size: 300}, k, time.Minute*5); err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+	}
+
+	if cnt := structCache.Count(); cnt != 3 {
+		t.Errorf("Count is not expected: %d", cnt)
+	}
+
+	if _, find := structCache.Get(&Key{Set: "set1", Pk: "9"}); !find {
+		t.Error("The latest value should be kept")
+	}
+
+	// growing of existing value evicts others
+	structCache.Put(sizedTestValue{size: 900}, &Key{Set: "set1", Pk: "9"}, time.Minute*5)
+	if cnt := structCache.Count(); cnt != 1 {
+		t.Errorf("Count is not expected: %d", cnt)
+	}
+}
+
+func TestStructCache_ByteLimit_TooLarge(t *testing.T) {
+	structCache := NewStructCacheObject(8000, nil, dummy.NewMetric())
+	structCache.RegisterCacheSetWithByteLimit("set1", 1000, 1000, nil)
+
+	err := structCache.Put(sizedTestValue{size: 2000}, &Key{Set: "set1", Pk: "1"}, time.Minute*5)
+	if err == nil {
+		t.Error("Value exceeding set byte limit should be rejected")
+	}
+}
+
+func TestStructCache_ByteLimit_ConcurrentPut(t *testing.T) {
+	structCache := NewStructCacheObject(8000, nil, dummy.NewMetric())
+	structCache.RegisterCacheSetWithByteLimit("set1", 1000, 100000, nil)
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 500; i++ {
+				k := &Key{Set: "set1", Pk: strconv.Itoa(i % 5)}
+				switch (g + i) % 4 {
+				case 0:
+					structCache.Put(sizedTestValue{size: 10 + i%7}, k, time.Minute)
+				case 1:
+					structCache.PutNegative(k, time.Minute)
+				case 2:
+					_, version, _ := structCache.GetVersioned(k)
+					structCache.CompareAndSwap(k, version, sizedTestValue{size: 20}, time.Minute)
+				default:
+					structCache.Incr(k, 1, time.Minute)
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	set, _ := structCache.getCacheSet(&Key{Set: "set1"})
+	var bytes int64
+	for _, shard := range set.shards {
+		bytes += shard.bytes
+	}
+	if set.bytes != bytes {
+		t.Errorf("Set bytes %d differ from shard bytes %d", set.bytes, bytes)
+	}
+	if set.count != int64(set.len()) {
+		t.Errorf("Set count %d differs from entries %d", set.count, set.len())
+	}
+}
@@ -0,0 +1,234 @@
+package cache
+
+import (
+	"encoding/gob"
+	"io"
+	"os"
+	"time"
+
+	"go-cache/errors"
+	"go-cache/metric"
+)
+
+// Snapshot is serializable copy of StructCache content
+type Snapshot struct {
+	Sets []SnapshotSet
+}
+
+// SnapshotSet is serializable copy of cache set with its limits
+type SnapshotSet struct {
+	Name       string
+	Limit      int
+	BytesLimit int64
+	Shards     int
+	Entries    []*Entry
+}
+
+// SnapshotCodec serializes snapshots
+type SnapshotCodec interface {
+	Encode(w io.Writer, snapshot *Snapshot) error
+	Decode(r io.Reader, snapshot *Snapshot) error
+}
+
+// GobSnapshotCodec serializes snapshots with encoding/gob.
+// Types of cached values have to be registered with gob.Register
+type GobSnapshotCodec struct{}
+
+// Encode implements SnapshotCodec
+func (GobSnapshotCodec) Encode(w io.Writer, snapshot *Snapshot) error {
+	return gob.NewEncoder(w).Encode(snapshot)
+}
+
+// Decode implements SnapshotCodec
+func (GobSnapshotCodec) Decode(r io.Reader, snapshot *Snapshot) error {
+	return gob.NewDecoder(r).Decode(snapshot)
+}
+
+// SnapshotConfig contains parameters of snapshot file, which is restored at construction and written on Close
+type SnapshotConfig struct {
+	Path  string
+	Codec SnapshotCodec
+}
+
+// NewStructCacheObjectWithSnapshot returns new instance of StructCache restored from snapshot file (if it exists).
+// The snapshot file is written on Close
+func NewStructCacheObjectWithSnapshot(limit int, logger IStructCacheLogger, metric metric.Metric, config SnapshotConfig) (*StructCache, error) {
+	cache := NewStructCacheObject(limit, logger, metric)
+	cache.snapshotPath = config.Path
+	if config.Codec != nil {
+		cache.snapshotCodec = config.Codec
+	}
+
+	file, err := os.Open(config.Path)
+	if os.IsNotExist(err) {
+		return cache, nil
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not open snapshot %q", config.Path)
+	}
+	defer file.Close()
+
+	if err := cache.LoadSnapshot(file); err != nil {
+		return nil, err
+	}
+
+	return cache, nil
+}
+
+// SetSnapshotCodec changes codec used by SaveSnapshot and LoadSnapshot, gob is used by default
+func (cache *StructCache) SetSnapshotCodec(codec SnapshotCodec) {
+	cache.snapshotCodec = codec
+}
+
+// SaveSnapshot writes all sets with their limits and not expired entries
+func (cache *StructCache) SaveSnapshot(w io.Writer) error {
+	snapshot := &Snapshot{}
+
+	cache.setsLock.RLock()
+	for _, set := range cache.setsCollection {
+		snapshot.Sets = append(snapshot.Sets, set.snapshot())
+	}
+	cache.setsLock.RUnlock()
+
+	if err := cache.snapshotCodec.Encode(w, snapshot); err != nil {
+		return errors.Wrap(err, "could not encode snapshot")
+	}
+
+	return nil
+}
+
+// LoadSnapshot restores sets and entries from snapshot, expired entries are skipped.
+// Sets registered before keep their own limits
+func (cache *StructCache) LoadSnapshot(r io.Reader) error {
+	snapshot := &Snapshot{}
+	if err := cache.snapshotCodec.Decode(r, snapshot); err != nil {
+		return errors.Wrap(err, "could not decode snapshot")
+	}
+
+	for _, snapshotSet := range snapshot.Sets {
+		options := cache.implicitSetOptions()
+		options.Limit = snapshotSet.Limit
+		options.BytesLimit = snapshotSet.BytesLimit
+		options.Shards = snapshotSet.Shards
+
+		err := cache.registerCacheSet(snapshotSet.Name, options, nil)
+		if err != nil && err != ErrSetAlreadyExists {
+			return err
+		}
+
+		set, _ := cache.getCacheSet(&Key{Set: snapshotSet.Name})
+
+		now := set.clock.Now()
+		restored := 0
+		for _, entry := range snapshotSet.Entries {
+			if entry == nil || entry.Key == nil || !entry.isValidAt(now) {
+				continue
+			}
+
+			if err := set.restore(entry); err != nil {
+				cache.logger.Warningf("struct_cache: could not restore %v: %s", entry.Key, err)
+				continue
+			}
+			restored++
+		}
+
+		if cache.logger.IsDebugEnabled() {
+			cache.logger.Debugf("struct_cache: restored %d entries of set %q", restored, snapshotSet.Name)
+		}
+	}
+
+	return nil
+}
+
+// saveSnapshotFile writes snapshot into configured file through temporary file
+func (cache *StructCache) saveSnapshotFile() error {
+	tmpPath := cache.snapshotPath + ".tmp"
+
+	file, err := os.Create(tmpPath)
+	if err != nil {
+		return errors.Wrapf(err, "could not create snapshot %q", tmpPath)
+	}
+
+	if err := cache.SaveSnapshot(file); err != nil {
+		file.Close()
+		return err
+	}
+
+	if err := file.Close(); err != nil {
+		return errors.Wrapf(err, "could not write snapshot %q", tmpPath)
+	}
+
+	return os.Rename(tmpPath, cache.snapshotPath)
+}
+
+// snapshot returns copy of set limits and not expired entries. Negative entries are skipped,
+// errors they keep can't be serialized
+func (set *cacheSet) snapshot() SnapshotSet {
+	snapshotSet := SnapshotSet{
+		Name:       set.name,
+		Limit:      set.limit(),
+		BytesLimit: set.bytesLimit,
+		Shards:     len(set.shards),
+	}
+
+	now := set.clock.Now()
+
+	for _, shard := range set.shards {
+		shard.keysLock.RLock()
+		for _, entry := range shard.elements {
+			if !entry.isValidAt(now) {
+				continue
+			}
+			if _, isNegative := entry.Data.(negativeValue); isNegative {
+				continue
+			}
+
+			snapshotSet.Entries = append(snapshotSet.Entries, &Entry{
+				Key:         entry.Key,
+				CreateDate:  entry.CreateDate,
+				EndDate:     entry.EndDate,
+				Expires:     entry.Expires,
+				SoftExpires: entry.SoftExpires,
+				Data:        entry.Data,
+			})
+		}
+		shard.keysLock.RUnlock()
+	}
+
+	return snapshotSet
+}
+
+// restore inserts entry keeping its dates, existing entry with the same key is replaced
+func (set *cacheSet) restore(entry *Entry) error {
+	shard := set.shard(entry.Key.Pk)
+
+	size, err := set.measure(shard, entry.Data)
+	if err != nil {
+		return err
+	}
+	entry.size = size
+
+	// TTLs are needed to refresh stale entry with the same freshness window
+	if entry.Expires.IsZero() {
+		entry.Expires = time.Unix(entry.EndDate, 0)
+	}
+	entry.ttl = entry.Expires.Sub(entry.CreateDate)
+	if !entry.SoftExpires.IsZero() {
+		entry.softTTL = entry.SoftExpires.Sub(entry.CreateDate)
+	}
+
+	var replaced []*Entry
+
+	shard.keysLock.Lock()
+	if old, ok := shard.elements[entry.Key.Pk]; ok {
+		shard.removeEntry(old)
+		replaced = append(replaced, old)
+	}
+	trimmed := set.insert(shard, entry)
+	shard.keysLock.Unlock()
+
+	set.evicted(replaced, EvictReplaced)
+	set.inserted(size, trimmed)
+
+	return nil
+}
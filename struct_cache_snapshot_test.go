@@ -0,0 +1,108 @@
+package cache
+
+import (
+	"bytes"
+	"go-cache/metric/dummy"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestStructCache_Snapshot_SaveLoad(t *testing.T) {
+	structCache := NewStructCacheObject(8000, nil, dummy.NewMetric())
+	structCache.RegisterShardedCacheSet("set1", 100, 4, nil)
+
+	structCache.Put("data1", &Key{Set: "set1", Pk: "1"}, time.Minute*5)
+	structCache.Put(42, &Key{Set: "set2", Pk: "2", Tags: []string{"tag"}}, time.Minute*5)
+	structCache.Put("expired", &Key{Set: "set2", Pk: "3"}, time.Minute*5)
+
+	set2, _ := structCache.getCacheSet(&Key{Set: "set2"})
+	set2.shard("3").elements["3"].Expires = time.Now().Add(-time.Millisecond)
+
+	_, created, _ := structCache.GetWithTime(&Key{Set: "set1", Pk: "1"})
+
+	var buf bytes.Buffer
+	if err := structCache.SaveSnapshot(&buf); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	restored := NewStructCacheObject(8000, nil, dummy.NewMetric())
+	if err := restored.LoadSnapshot(&buf); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	if cnt := restored.Count(); cnt != 2 {
+		t.Errorf("Count is not expected: %d", cnt)
+	}
+
+	data, restoredCreated, find := restored.GetWithTime(&Key{Set: "set1", Pk: "1"})
+	if !find || data != "data1" {
+		t.Errorf("Data is not expected: %v", data)
+	}
+	if !restoredCreated.Equal(created) {
+		t.Errorf("Create date is not kept: %s, expected: %s", restoredCreated, created)
+	}
+
+	set1, _ := restored.getCacheSet(&Key{Set: "set1"})
+	if set1.limit() != 100 || len(set1.shards) != 4 {
+		t.Errorf("Set limits are not kept: %d, %d", set1.limit(), len(set1.shards))
+	}
+
+	restored.Remove(&Key{Set: "set2", Tags: []string{"tag"}})
+	if _, find := restored.Get(&Key{Set: "set2", Pk: "2"}); find {
+		t.Error("Restored entry should keep tags")
+	}
+}
+
+func TestStructCache_Snapshot_File(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "struct_cache.snapshot")
+
+	structCache, err := NewStructCacheObjectWithSnapshot(8000, nil, dummy.NewMetric(), SnapshotConfig{Path: path})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	structCache.Put("data", &Key{Set: "set1", Pk: "1"}, time.Minute*5)
+	structCache.Close()
+
+	if _, err := os.Stat(path); err != nil {
+		t.Fatalf("Snapshot should be written on close: %s", err)
+	}
+
+	restored, err := NewStructCacheObjectWithSnapshot(8000, nil, dummy.NewMetric(), SnapshotConfig{Path: path})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	if data, find := restored.Get(&Key{Set: "set1", Pk: "1"}); !find || data != "data" {
+		t.Errorf("Data is not expected: %v", data)
+	}
+}
+
+func TestStructCache_Snapshot_NegativeEntries(t *testing.T) {
+	structCache := NewStructCacheObject(8000, nil, dummy.NewMetric())
+
+	structCache.Put("data", &Key{Set: "set1", Pk: "1"}, time.Minute)
+	structCache.PutNegative(&Key{Set: "set1", Pk: "2"}, time.Minute)
+	structCache.GetOrLoadWithErrorTTL(&Key{Set: "set1", Pk: "3"}, time.Minute, time.Minute, func() (interface{}, error) {
+		return nil, ErrNotFound
+	})
+
+	var buf bytes.Buffer
+	if err := structCache.SaveSnapshot(&buf); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	restored := NewStructCacheObject(8000, nil, dummy.NewMetric())
+	if err := restored.LoadSnapshot(&buf); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	if data, ok := restored.Get(&Key{Set: "set1", Pk: "1"}); !ok || data != "data" {
+		t.Errorf("Normal entry should be restored, got %v, %v", data, ok)
+	}
+	if restored.Count() != 1 {
+		t.Errorf("Negative entries should be skipped, got %d entries", restored.Count())
+	}
+}
@@ -1,7 +1,9 @@
 package cache
 
 import (
+	"context"
 	"go-cache/metric/dummy"
+	"strconv"
 	"testing"
 	"time"
 )
@@ -218,7 +220,7 @@ func TestStructCache_RegisterCacheSet_AlreadyExists(t *testing.T) {
 
 func TestStructCache_Collector_Ok(t *testing.T) {
 	structCache := NewStructCacheObject(2, nil, dummy.NewMetric())
-	structCache.ticker = time.NewTicker(time.Millisecond * 1)
+	structCache.defaultOptions.CollectorInterval = time.Millisecond * 1
 	k := &Key{
 		Set: "set1",
 		Pk:  "1",
@@ -237,7 +239,7 @@ func TestStructCache_Collector_Ok(t *testing.T) {
 
 func TestStructCache_Close_Ok(t *testing.T) {
 	structCache := NewStructCacheObject(2, nil, dummy.NewMetric())
-	structCache.ticker = time.NewTicker(time.Millisecond * 1)
+	structCache.defaultOptions.CollectorInterval = time.Millisecond * 1
 	k := &Key{
 		Set: "set1",
 		Pk:  "1",
@@ -299,7 +301,9 @@ func TestStructCache_Get_NotFound(t *testing.T) {
 }
 
 func TestStructCache_Get_Expired(t *testing.T) {
+	clock := NewFakeClock(time.Now())
 	structCache := NewStructCacheObject(1000, nil, dummy.NewMetric())
+	structCache.SetClock(clock)
 	structCache.SetLimit(1)
 	k := &Key{
 		Set: "set1",
@@ -308,7 +312,7 @@ func TestStructCache_Get_Expired(t *testing.T) {
 	data := "data"
 	structCache.Put(data, k, time.Millisecond*1)
 
-	time.Sleep(time.Millisecond * 5)
+	clock.Advance(time.Millisecond)
 
 	result, exists := structCache.Get(k)
 
@@ -326,3 +330,180 @@ func TestStructCache_Get_Expired(t *testing.T) {
 		t.Error("Expired key should be cleaned on Get operation")
 	}
 }
+
+func TestStructCache_RegisterShardedCacheSet_Limit(t *testing.T) {
+	structCache := NewStructCacheObject(8000, nil, dummy.NewMetric())
+
+	structCache.RegisterShardedCacheSet("set1", 10, 4, nil)
+	for i := 0; i < 100; i++ {
+		k := &Key{
+			Set: "set1",
+			Pk:  strconv.Itoa(i),
+		}
+		structCache.Put(i, k, time.Minute*5)
+	}
+
+	cnt := structCache.Count()
+
+	if cnt > 10 || cnt == 0 {
+		t.Errorf("Count is not expected: %d", cnt)
+	}
+}
+
+func TestStructCache_RegisterShardedCacheSet_Ok(t *testing.T) {
+	structCache := NewStructCacheObject(8000, nil, dummy.NewMetric())
+
+	structCache.RegisterShardedCacheSet("set1", 1000, 8, nil)
+	for i := 0; i < 100; i++ {
+		k := &Key{
+			Set: "set1",
+			Pk:  "key" + strconv.Itoa(i),
+		}
+		structCache.Put(i, k, time.Minute*5)
+	}
+
+	if cnt := structCache.Count(); cnt != 100 {
+		t.Errorf("Count is not expected: %d", cnt)
+	}
+
+	data, find := structCache.Get(&Key{Set: "set1", Pk: "key42"})
+	if !find || data != 42 {
+		t.Error("Data is not expected")
+	}
+
+	if found := structCache.Find("key", 5); len(found) != 5 {
+		t.Errorf("Find returns wrong number of keys: %d", len(found))
+	}
+
+	structCache.Remove(&Key{Set: "set1", Pk: "key42"})
+	if cnt := structCache.Count(); cnt != 99 {
+		t.Errorf("Count is not expected: %d", cnt)
+	}
+
+	if flushed := structCache.Flush(); flushed != 99 {
+		t.Errorf("Flush returns wrong number of entries: %d", flushed)
+	}
+
+	if cnt := structCache.Count(); cnt != 0 {
+		t.Errorf("Count is not expected: %d", cnt)
+	}
+}
+
+func TestShardLimits(t *testing.T) {
+	limits := shardLimits(10, 4)
+	if len(limits) != 4 || limits[0] != 3 || limits[1] != 3 || limits[2] != 2 || limits[3] != 2 {
+		t.Errorf("Limits are not expected: %v", limits)
+	}
+
+	limits = shardLimits(2, 8)
+	if len(limits) != 2 || limits[0] != 1 || limits[1] != 1 {
+		t.Errorf("Shards count should not exceed limit: %v", limits)
+	}
+}
+
+func TestStructCache_Remove_ByTag(t *testing.T) {
+	structCache := NewStructCacheObject(8000, nil, dummy.NewMetric())
+	structCache.RegisterShardedCacheSet("set1", 1000, 4, nil)
+
+	for i := 0; i < 10; i++ {
+		k := &Key{
+			Set:  "set1",
+			Pk:   strconv.Itoa(i),
+			Tags: []string{"brand:" + strconv.Itoa(i%2)},
+		}
+		structCache.Put(i, k, time.Minute*5)
+	}
+
+	structCache.Remove(&Key{Set: "set1", Tags: []string{"brand:1"}})
+
+	if cnt := structCache.Count(); cnt != 5 {
+		t.Errorf("Count is not expected: %d", cnt)
+	}
+
+	if _, find := structCache.Get(&Key{Set: "set1", Pk: "3"}); find {
+		t.Error("Tagged entry should be removed")
+	}
+
+	if _, find := structCache.Get(&Key{Set: "set1", Pk: "4"}); !find {
+		t.Error("Entry with other tag should be kept")
+	}
+}
+
+func TestStructCache_Remove_ByTag_IndexCleanup(t *testing.T) {
+	structCache := NewStructCacheObject(8000, nil, dummy.NewMetric())
+	structCache.RegisterCacheSet("set1", 1, nil)
+
+	structCache.Put("data", &Key{Set: "set1", Pk: "1", Tags: []string{"tag"}}, time.Minute*5)
+	// evicts the first entry
+	structCache.Put("data", &Key{Set: "set1", Pk: "2"}, time.Minute*5)
+	// retagged entry leaves the old tag
+	structCache.Put("data", &Key{Set: "set1", Pk: "2", Tags: []string{"other"}}, time.Minute*5)
+
+	set, _ := structCache.getCacheSet(&Key{Set: "set1"})
+	if tags := set.shards[0].tags; len(tags) != 1 || len(tags["other"]) != 1 {
+		t.Errorf("Tags index is not expected: %v", tags)
+	}
+
+	structCache.Flush()
+	if tags := set.shards[0].tags; len(tags) != 0 {
+		t.Errorf("Tags index should be empty after flush: %v", tags)
+	}
+}
+
+func TestStructCache_Get_SubSecondTTL(t *testing.T) {
+	structCache := NewStructCacheObject(1000, nil, dummy.NewMetric())
+	k := &Key{
+		Set: "set1",
+		Pk:  "1",
+	}
+	structCache.Put("data", k, time.Millisecond*500)
+
+	if _, exists := structCache.Get(k); !exists {
+		t.Error("Key with sub-second TTL shouldn't be born expired")
+	}
+
+	structCache.Put("data", k, time.Millisecond*20)
+	time.Sleep(time.Millisecond * 30)
+
+	if _, exists := structCache.Get(k); exists {
+		t.Error("This key is expired")
+	}
+}
+
+func TestStructCache_Ctx(t *testing.T) {
+	structCache := NewStructCacheObject(8000, nil, dummy.NewMetric())
+	k := &Key{
+		Set: "set1",
+		Pk:  "1",
+	}
+
+	if err := structCache.PutCtx(context.Background(), "data", k, time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	if data, ok, err := structCache.GetCtx(context.Background(), k); err != nil || !ok || data != "data" {
+		t.Errorf("Unexpected result %v, %t, %v", data, ok, err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, _, err := structCache.GetCtx(ctx, k); err != context.Canceled {
+		t.Errorf("Expected canceled error, got %v", err)
+	}
+	if err := structCache.PutCtx(ctx, "other", k, time.Minute); err != context.Canceled {
+		t.Errorf("Expected canceled error, got %v", err)
+	}
+	if err := structCache.RemoveCtx(ctx, k); err != context.Canceled {
+		t.Errorf("Expected canceled error, got %v", err)
+	}
+	if data, _ := structCache.Get(k); data != "data" {
+		t.Errorf("Canceled calls should not change cache, got %v", data)
+	}
+
+	if err := structCache.RemoveCtx(context.Background(), k); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := structCache.Get(k); ok {
+		t.Error("Data should be removed")
+	}
+}
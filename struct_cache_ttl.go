@@ -0,0 +1,107 @@
+package cache
+
+import (
+	"time"
+
+	"go-cache/errors"
+	"go-cache/metric"
+)
+
+// TTL returns remaining time to live of entry by key, negative entries included
+func (cache *StructCache) TTL(key *Key) (time.Duration, bool) {
+	set, ok := cache.getCacheSet(key)
+	if !ok {
+		return 0, false
+	}
+
+	return set.ttl(key)
+}
+
+// Touch extends life of entry by key to ttl from now without rewriting its value.
+// Zero ttl means default TTL of the set, ttl is limited by max TTL of the set.
+// Returns false if there is no such entry
+func (cache *StructCache) Touch(key *Key, ttl time.Duration) (bool, error) {
+	set, ok := cache.getCacheSet(key)
+	if !ok {
+		return false, nil
+	}
+
+	if _, ttl = set.resolveTTL(0, ttl); ttl <= 0 {
+		return false, errors.New("Cannot touch element (ttl is not assign)")
+	}
+
+	if cache.logger.IsDebugEnabled() {
+		cache.logger.Debugf("struct_cache: TOUCH %q with TTL: %s", key, ttl)
+	}
+
+	return set.touch(key, ttl), nil
+}
+
+// PutUntil puts element which expires at given time
+func (cache *StructCache) PutUntil(data interface{}, key *Key, until time.Time) error {
+	ttl := until.Sub(cache.clockOf(key).Now())
+	if ttl <= 0 {
+		return errors.Errorf("Cannot put element (end of life %s has passed)", until)
+	}
+
+	return cache.put(data, key, 0, ttl)
+}
+
+// clockOf returns clock of set of the key, or clock of the cache if the set is not registered yet
+func (cache *StructCache) clockOf(key *Key) Clock {
+	cache.setsLock.RLock()
+	defer cache.setsLock.RUnlock()
+
+	if set, ok := cache.setsCollection[key.Set]; ok {
+		return set.clock
+	}
+
+	return cache.clock
+}
+
+func (set *cacheSet) ttl(key *Key) (time.Duration, bool) {
+	shard := set.shard(key.Pk)
+	now := set.clock.Now()
+
+	shard.keysLock.RLock()
+	defer shard.keysLock.RUnlock()
+
+	entry, ok := shard.elements[key.Pk]
+	if !ok || !entry.isValidAt(now) {
+		return 0, false
+	}
+
+	return entry.expiresAt().Sub(now), true
+}
+
+func (set *cacheSet) touch(key *Key, ttl time.Duration) bool {
+	ts := time.Now()
+	shard := set.shard(key.Pk)
+	now := set.clock.Now()
+
+	shard.keysLock.Lock()
+	entry, ok := shard.elements[key.Pk]
+	if !ok {
+		shard.keysLock.Unlock()
+		return false
+	}
+
+	if !entry.isValidAt(now) {
+		shard.removeEntry(entry)
+		shard.keysLock.Unlock()
+
+		set.evicted([]*Entry{entry}, EvictExpired)
+		return false
+	}
+
+	entry.setExpires(now.Add(ttl))
+	shard.reschedule(entry)
+	shard.keysLock.Unlock()
+
+	set.metric.ObserveRT(set.labels(map[string]string{
+		metric.LabelSet:       key.Set,
+		metric.LabelOperation: "touch",
+	}), metric.SinceMs(ts))
+
+	return true
+}
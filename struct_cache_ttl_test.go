@@ -0,0 +1,110 @@
+package cache
+
+import (
+	"testing"
+	"time"
+
+	"go-cache/metric/dummy"
+)
+
+func TestStructCache_TTL(t *testing.T) {
+	clock := NewFakeClock(time.Now())
+	structCache := NewStructCacheObject(8000, nil, dummy.NewMetric())
+	structCache.SetClock(clock)
+	k := &Key{Set: "set1", Pk: "1"}
+
+	if _, ok := structCache.TTL(k); ok {
+		t.Error("TTL of missing key should not be found")
+	}
+
+	structCache.Put("data", k, time.Minute)
+	clock.Advance(10 * time.Second)
+
+	if ttl, ok := structCache.TTL(k); !ok || ttl != 50*time.Second {
+		t.Errorf("Unexpected TTL %s, %v", ttl, ok)
+	}
+
+	clock.Advance(time.Minute)
+
+	if _, ok := structCache.TTL(k); ok {
+		t.Error("TTL of expired key should not be found")
+	}
+}
+
+func TestStructCache_Touch(t *testing.T) {
+	clock := NewFakeClock(time.Now())
+	structCache := NewStructCacheObject(8000, nil, dummy.NewMetric())
+	structCache.SetClock(clock)
+	k := &Key{Set: "set1", Pk: "1"}
+
+	if ok, err := structCache.Touch(k, time.Minute); ok || err != nil {
+		t.Errorf("Missing key should not be touched: %v, %v", ok, err)
+	}
+
+	structCache.Put("data", k, time.Minute)
+	clock.Advance(50 * time.Second)
+
+	if ok, err := structCache.Touch(k, time.Minute); !ok || err != nil {
+		t.Fatalf("Key should be touched: %v, %v", ok, err)
+	}
+
+	clock.Advance(50 * time.Second)
+
+	if data, ok := structCache.Get(k); !ok || data != "data" {
+		t.Errorf("Touched key should be alive, got %v, %v", data, ok)
+	}
+	if ttl, _ := structCache.TTL(k); ttl != 10*time.Second {
+		t.Errorf("Unexpected TTL %s", ttl)
+	}
+
+	clock.Advance(10 * time.Second)
+	structCache.setsCollection["set1"].collect()
+
+	if structCache.Count() != 0 {
+		t.Errorf("Touched key should be collected after new TTL, count %d", structCache.Count())
+	}
+	if ok, _ := structCache.Touch(k, time.Minute); ok {
+		t.Error("Expired key should not be touched")
+	}
+}
+
+func TestStructCache_Touch_MaxTTL(t *testing.T) {
+	clock := NewFakeClock(time.Now())
+	structCache := NewStructCacheObject(8000, nil, dummy.NewMetric())
+	structCache.SetClock(clock)
+	structCache.RegisterCacheSetWithOptions("set1", CacheSetOptions{Limit: 10, MaxTTL: time.Minute})
+	k := &Key{Set: "set1", Pk: "1"}
+
+	structCache.Put("data", k, time.Minute)
+
+	if ok, err := structCache.Touch(k, time.Hour); !ok || err != nil {
+		t.Fatalf("Key should be touched: %v, %v", ok, err)
+	}
+	if ttl, _ := structCache.TTL(k); ttl != time.Minute {
+		t.Errorf("TTL should be limited by max TTL, got %s", ttl)
+	}
+}
+
+func TestStructCache_PutUntil(t *testing.T) {
+	clock := NewFakeClock(time.Now())
+	structCache := NewStructCacheObject(8000, nil, dummy.NewMetric())
+	structCache.SetClock(clock)
+	k := &Key{Set: "set1", Pk: "1"}
+
+	if err := structCache.PutUntil("data", k, clock.Now().Add(-time.Second)); err == nil {
+		t.Error("Put with passed end of life should fail")
+	}
+
+	if err := structCache.PutUntil("data", k, clock.Now().Add(time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+	if ttl, ok := structCache.TTL(k); !ok || ttl != time.Minute {
+		t.Errorf("Unexpected TTL %s, %v", ttl, ok)
+	}
+
+	clock.Advance(time.Minute)
+
+	if _, ok := structCache.Get(k); ok {
+		t.Error("Key should expire at given time")
+	}
+}
@@ -0,0 +1,67 @@
+package cache
+
+import (
+	"time"
+)
+
+// TypedStructCache is type-safe facade over IStructCache. It binds values of type T to one cache set,
+// so the set can never hold mixed types and type mismatches are caught by compiler
+type TypedStructCache[T any] struct {
+	cache IStructCache
+	set   string
+}
+
+// NewTypedStructCache registers cache set setName in given cache and returns typed facade bound to it.
+// Returns ErrSetAlreadyExists if set was already registered (by other facade or by implicit Put)
+func NewTypedStructCache[T any](cache IStructCache, setName string, limit int, ticker *time.Ticker) (*TypedStructCache[T], error) {
+	if err := cache.RegisterCacheSet(setName, limit, ticker); err != nil {
+		return nil, err
+	}
+
+	return &TypedStructCache[T]{
+		cache: cache,
+		set:   setName,
+	}, nil
+}
+
+// Set returns name of cache set bound to facade
+func (typed *TypedStructCache[T]) Set() string {
+	return typed.set
+}
+
+// Key returns cache key for given primary key within bound set
+func (typed *TypedStructCache[T]) Key(pk string) *Key {
+	return &Key{Set: typed.set, Pk: pk}
+}
+
+// Get returns value by primary key
+func (typed *TypedStructCache[T]) Get(pk string) (T, bool) {
+	data, _, ok := typed.GetWithTime(pk)
+
+	return data, ok
+}
+
+// GetWithTime returns value and create time(UTC) by primary key
+func (typed *TypedStructCache[T]) GetWithTime(pk string) (T, time.Time, bool) {
+	var result T
+
+	data, created, ok := typed.cache.GetWithTime(typed.Key(pk))
+	if !ok {
+		return result, created, false
+	}
+
+	// set might be filled with untyped Put bypassing the facade
+	result, ok = data.(T)
+
+	return result, created, ok
+}
+
+// Put puts value into bound set
+func (typed *TypedStructCache[T]) Put(data T, pk string, ttl time.Duration) error {
+	return typed.cache.Put(data, typed.Key(pk), ttl)
+}
+
+// Remove removes value by primary key
+func (typed *TypedStructCache[T]) Remove(pk string) {
+	typed.cache.Remove(typed.Key(pk))
+}
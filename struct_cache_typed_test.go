@@ -0,0 +1,78 @@
+package cache
+
+import (
+	"go-cache/metric/dummy"
+	"testing"
+	"time"
+)
+
+type typedTestProduct struct {
+	ID   int
+	Name string
+}
+
+func TestTypedStructCache_Get_Ok(t *testing.T) {
+	structCache := NewStructCacheObject(8000, nil, dummy.NewMetric())
+	products, err := NewTypedStructCache[typedTestProduct](structCache, "products", 100, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	data := typedTestProduct{ID: 1, Name: "phone"}
+	if err := products.Put(data, "1", time.Minute*5); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	result, find := products.Get("1")
+	if !find {
+		t.Error("Data was not found")
+	}
+
+	if result != data {
+		t.Error("Data is not expected")
+	}
+
+	if _, find := structCache.Get(&Key{Set: "products", Pk: "1"}); !find {
+		t.Error("Data should be stored in bound set")
+	}
+}
+
+func TestTypedStructCache_Get_WrongType(t *testing.T) {
+	structCache := NewStructCacheObject(8000, nil, dummy.NewMetric())
+	products, _ := NewTypedStructCache[typedTestProduct](structCache, "products", 100, nil)
+
+	structCache.Put("data", products.Key("1"), time.Minute*5)
+
+	result, find := products.Get("1")
+	if find {
+		t.Error("Value of other type shouldn't be returned")
+	}
+
+	if result != (typedTestProduct{}) {
+		t.Error("Data should be zero value")
+	}
+}
+
+func TestTypedStructCache_Remove_Ok(t *testing.T) {
+	structCache := NewStructCacheObject(8000, nil, dummy.NewMetric())
+	products, _ := NewTypedStructCache[typedTestProduct](structCache, "products", 100, nil)
+
+	products.Put(typedTestProduct{ID: 1}, "1", time.Minute*5)
+	products.Remove("1")
+
+	if _, find := products.Get("1"); find {
+		t.Error("Data should be removed")
+	}
+}
+
+func TestTypedStructCache_SetAlreadyBound(t *testing.T) {
+	structCache := NewStructCacheObject(8000, nil, dummy.NewMetric())
+
+	if _, err := NewTypedStructCache[typedTestProduct](structCache, "products", 100, nil); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	if _, err := NewTypedStructCache[string](structCache, "products", 100, nil); err != ErrSetAlreadyExists {
+		t.Error("Set should not be bound to second type")
+	}
+}
@@ -0,0 +1,252 @@
+package cache
+
+import (
+	"reflect"
+	"strconv"
+	"time"
+	"unsafe"
+
+	"go-cache/errors"
+)
+
+// ValueSafety protects cached values from being changed by callers through shared memory
+type ValueSafety int
+
+const (
+	// ValueSafetyOff stores and returns values as they are
+	ValueSafetyOff ValueSafety = iota
+	// ValueSafetyStrict rejects values containing pointers, maps, slices or channels. time.Time and unexported
+	// fields of types from other packages are accepted
+	ValueSafetyStrict
+	// ValueSafetyCopyOnPut stores deep copy of value, so caller can change its value after Put
+	ValueSafetyCopyOnPut
+	// ValueSafetyCopyOnGet returns deep copy of stored value, so caller can change returned value
+	ValueSafetyCopyOnGet
+	// ValueSafetyCopy copies values both on put and on get
+	ValueSafetyCopy
+	// ValueSafetyNoPointers rejects pointer values, their fields still may be changed through other references
+	ValueSafetyNoPointers
+)
+
+// String implements fmt.Stringer interface
+func (mode ValueSafety) String() string {
+	switch mode {
+	case ValueSafetyOff:
+		return "off"
+	case ValueSafetyStrict:
+		return "strict"
+	case ValueSafetyCopyOnPut:
+		return "copy_on_put"
+	case ValueSafetyCopyOnGet:
+		return "copy_on_get"
+	case ValueSafetyCopy:
+		return "copy"
+	case ValueSafetyNoPointers:
+		return "no_pointers"
+	}
+
+	return "unknown"
+}
+
+// onPut returns value which should be stored
+func (mode ValueSafety) onPut(data interface{}) (interface{}, error) {
+	switch mode {
+	case ValueSafetyStrict:
+		v := reflect.ValueOf(data)
+		if err := checkImmutable(v, "value", ownPackage(v)); err != nil {
+			return nil, err
+		}
+	case ValueSafetyCopyOnPut, ValueSafetyCopy:
+		return deepCopy(data), nil
+	case ValueSafetyNoPointers:
+		if data != nil && isPointer(data) {
+			return nil, errors.Errorf("struct_cache: value of type %T is pointer, it is prohibited in no_pointers mode", data)
+		}
+	}
+
+	return data, nil
+}
+
+// onGet returns value which should be returned to caller
+func (mode ValueSafety) onGet(data interface{}) interface{} {
+	if mode == ValueSafetyCopyOnGet || mode == ValueSafetyCopy {
+		return deepCopy(data)
+	}
+
+	return data
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// ownPackage returns package of type of cached value. Unexported fields of types from other packages
+// can't be changed by caller, they aren't walked on check and copy
+func ownPackage(v reflect.Value) string {
+	if !v.IsValid() {
+		return ""
+	}
+
+	t := v.Type()
+	for t.Name() == "" && (t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice || t.Kind() == reflect.Array || t.Kind() == reflect.Map) {
+		t = t.Elem()
+	}
+
+	return t.PkgPath()
+}
+
+// walkField tells whether field of struct is checked or copied deeply
+func walkField(field reflect.StructField, pkg string) bool {
+	return field.PkgPath == "" || field.PkgPath == pkg
+}
+
+// checkImmutable returns error if value references memory which may be changed by caller.
+// time.Time is immutable value, unexported fields of types from packages other than pkg are skipped
+func checkImmutable(v reflect.Value, path, pkg string) error {
+	if v.IsValid() && v.Type() == timeType {
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr, reflect.UnsafePointer, reflect.Map, reflect.Slice, reflect.Chan:
+		return errors.Errorf("struct_cache: %s of type %s is mutable, it is prohibited in strict mode", path, v.Type())
+
+	case reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		return checkImmutable(v.Elem(), path, pkg)
+
+	case reflect.Array:
+		for i := 0; i < v.Len() && hasIndirect(v.Type().Elem()); i++ {
+			if err := checkImmutable(v.Index(i), path+"["+strconv.Itoa(i)+"]", pkg); err != nil {
+				return err
+			}
+		}
+
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Type().Field(i)
+			if !walkField(field, pkg) {
+				continue
+			}
+			if err := checkImmutable(v.Field(i), path+"."+field.Name, pkg); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// deepCopy returns copy of data not sharing memory with it, channels and functions are shared.
+// Unexported struct fields are copied too, fields of types from other packages and time.Time are copied by value
+func deepCopy(data interface{}) interface{} {
+	if data == nil {
+		return nil
+	}
+
+	src := addressable(reflect.ValueOf(data))
+	dst := reflect.New(src.Type()).Elem()
+	copyValue(dst, src, ownPackage(src), make(map[uintptr]reflect.Value))
+
+	return dst.Interface()
+}
+
+// copyValue copies src into settable dst, seen keeps copies of pointers to preserve cycles and sharing.
+// Structs have to be addressable to read their unexported fields
+func copyValue(dst, src reflect.Value, pkg string, seen map[uintptr]reflect.Value) {
+	if src.Type() == timeType {
+		dst.Set(src)
+		return
+	}
+
+	switch src.Kind() {
+	case reflect.Ptr:
+		if src.IsNil() {
+			return
+		}
+		if copied, ok := seen[src.Pointer()]; ok {
+			dst.Set(copied)
+			return
+		}
+		ptr := reflect.New(src.Type().Elem())
+		seen[src.Pointer()] = ptr
+		copyValue(ptr.Elem(), src.Elem(), pkg, seen)
+		dst.Set(ptr)
+
+	case reflect.Interface:
+		if src.IsNil() {
+			return
+		}
+		elem := addressable(src.Elem())
+		copied := reflect.New(elem.Type()).Elem()
+		copyValue(copied, elem, pkg, seen)
+		dst.Set(copied)
+
+	case reflect.Map:
+		if src.IsNil() {
+			return
+		}
+		m := reflect.MakeMapWithSize(src.Type(), src.Len())
+		iter := src.MapRange()
+		for iter.Next() {
+			key := reflect.New(src.Type().Key()).Elem()
+			copyValue(key, addressable(iter.Key()), pkg, seen)
+			value := reflect.New(src.Type().Elem()).Elem()
+			copyValue(value, addressable(iter.Value()), pkg, seen)
+			m.SetMapIndex(key, value)
+		}
+		dst.Set(m)
+
+	case reflect.Slice:
+		if src.IsNil() {
+			return
+		}
+		s := reflect.MakeSlice(src.Type(), src.Len(), src.Len())
+		if hasIndirect(src.Type().Elem()) {
+			for i := 0; i < src.Len(); i++ {
+				copyValue(s.Index(i), src.Index(i), pkg, seen)
+			}
+		} else {
+			reflect.Copy(s, src)
+		}
+		dst.Set(s)
+
+	case reflect.Array:
+		for i := 0; i < src.Len(); i++ {
+			copyValue(dst.Index(i), src.Index(i), pkg, seen)
+		}
+
+	case reflect.Struct:
+		for i := 0; i < src.NumField(); i++ {
+			if walkField(src.Type().Field(i), pkg) {
+				copyValue(exported(dst.Field(i)), exported(src.Field(i)), pkg, seen)
+			} else {
+				exported(dst.Field(i)).Set(exported(src.Field(i)))
+			}
+		}
+
+	default:
+		dst.Set(src)
+	}
+}
+
+// addressable returns addressable copy of value
+func addressable(v reflect.Value) reflect.Value {
+	if v.CanAddr() {
+		return v
+	}
+
+	copied := reflect.New(v.Type()).Elem()
+	copied.Set(v)
+
+	return copied
+}
+
+// exported returns value of addressable unexported struct field which can be read and set
+func exported(v reflect.Value) reflect.Value {
+	if v.CanSet() {
+		return v
+	}
+
+	return reflect.NewAt(v.Type(), unsafe.Pointer(v.UnsafeAddr())).Elem()
+}
@@ -0,0 +1,180 @@
+package cache
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"go-cache/metric/dummy"
+)
+
+type safetyNode struct {
+	Name     string
+	Tags     []string
+	Attrs    map[string]int
+	next     *safetyNode
+	private  []int
+	Any      interface{}
+	Fixed    [2][]byte
+	Callback func()
+}
+
+func TestDeepCopy(t *testing.T) {
+	node := &safetyNode{
+		Name:    "node",
+		Tags:    []string{"a", "b"},
+		Attrs:   map[string]int{"x": 1},
+		private: []int{1, 2},
+		Any:     []string{"any"},
+		Fixed:   [2][]byte{[]byte("f")},
+	}
+	node.next = node
+
+	copied := deepCopy(node).(*safetyNode)
+	if !reflect.DeepEqual(node, copied) {
+		t.Fatalf("Copy %+v differs from %+v", copied, node)
+	}
+	if copied == node || copied.next != copied {
+		t.Error("Cycle should point to the copy")
+	}
+
+	copied.Tags[0] = "changed"
+	copied.Attrs["x"] = 2
+	copied.private[0] = 100
+	copied.Any.([]string)[0] = "changed"
+	copied.Fixed[0][0] = 'c'
+
+	if node.Tags[0] != "a" || node.Attrs["x"] != 1 || node.private[0] != 1 || node.Any.([]string)[0] != "any" || node.Fixed[0][0] != 'f' {
+		t.Errorf("Original is changed by copy: %+v", node)
+	}
+
+	if deepCopy(nil) != nil {
+		t.Error("Copy of nil should be nil")
+	}
+	if deepCopy(42) != 42 {
+		t.Error("Copy of scalar should be equal")
+	}
+}
+
+func TestCheckImmutable(t *testing.T) {
+	type flat struct {
+		A int
+		B string
+		C [2]float64
+	}
+	type nested struct {
+		Flat flat
+		list []int
+	}
+
+	valid := []interface{}{1, "s", flat{}, [3]int{}, struct{ I interface{} }{I: 1}}
+	for _, v := range valid {
+		if _, err := ValueSafetyStrict.onPut(v); err != nil {
+			t.Errorf("Value %#v should be accepted: %s", v, err)
+		}
+	}
+
+	invalid := []interface{}{&flat{}, []int{}, map[string]int{}, make(chan int), nested{}, struct{ I interface{} }{I: []int{}}}
+	for _, v := range invalid {
+		if _, err := ValueSafetyStrict.onPut(v); err == nil {
+			t.Errorf("Value %#v should be rejected", v)
+		}
+	}
+}
+
+func TestValueSafety_Time(t *testing.T) {
+	type dto struct {
+		Name    string
+		Updated time.Time
+		times   [2]time.Time
+	}
+
+	updated := time.Date(2020, 1, 2, 3, 4, 5, 0, time.FixedZone("test", 3600))
+	value := dto{Name: "dto", Updated: updated, times: [2]time.Time{updated}}
+
+	if _, err := ValueSafetyStrict.onPut(value); err != nil {
+		t.Errorf("Struct with time should be accepted in strict mode: %s", err)
+	}
+	if _, err := ValueSafetyStrict.onPut(updated); err != nil {
+		t.Errorf("Time should be accepted in strict mode: %s", err)
+	}
+
+	copied := deepCopy(value).(dto)
+	if copied.Updated != updated || copied.times[0] != updated {
+		t.Errorf("Copied time should be equal: %s", copied.Updated)
+	}
+	if copied.Updated.Location() != updated.Location() {
+		t.Error("Location of copied time should be shared")
+	}
+
+	if copied := deepCopy(&value).(*dto); copied == &value || copied.Updated != updated {
+		t.Errorf("Copy of pointer is not expected: %+v", copied)
+	}
+}
+
+func TestStructCache_ValueSafety(t *testing.T) {
+	structCache := NewStructCacheObject(100, nil, dummy.NewMetric())
+	structCache.RegisterCacheSetWithOptions("strict", CacheSetOptions{Limit: 10, ValueSafety: ValueSafetyStrict})
+	structCache.RegisterCacheSetWithOptions("copy", CacheSetOptions{Limit: 10, ValueSafety: ValueSafetyCopy})
+	structCache.RegisterCacheSetWithOptions("off", CacheSetOptions{Limit: 10})
+
+	if err := structCache.Put([]int{1}, &Key{Set: "strict", Pk: "1"}, time.Minute); err == nil {
+		t.Error("Slice should be rejected in strict mode")
+	}
+
+	value := []int{1}
+	k := &Key{Set: "copy", Pk: "1"}
+	if err := structCache.Put(value, k, time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	value[0] = 2
+
+	data, _ := structCache.Get(k)
+	if data.([]int)[0] != 1 {
+		t.Error("Cached value should not be changed by caller after Put")
+	}
+	data.([]int)[0] = 3
+	if data, _ := structCache.Get(k); data.([]int)[0] != 1 {
+		t.Error("Cached value should not be changed by caller after Get")
+	}
+
+	k = &Key{Set: "off", Pk: "1"}
+	structCache.Put(value, k, time.Minute)
+	value[0] = 4
+	if data, _ := structCache.Get(k); data.([]int)[0] != 4 {
+		t.Error("Value should be shared in off mode")
+	}
+}
+
+func TestStructCacheDummy_ValueSafety(t *testing.T) {
+	dummyCache := NewStructCacheObjectDummy(nil)
+	structCache := NewStructCacheObject(100, nil, dummy.NewMetric())
+
+	for _, cache := range []interface {
+		IStructCache
+		RegisterCacheSetWithOptions(string, CacheSetOptions) error
+	}{dummyCache, structCache} {
+		cache.RegisterCacheSetWithOptions("strict", CacheSetOptions{Limit: 10, ValueSafety: ValueSafetyStrict})
+
+		if err := cache.Put(map[string]int{}, &Key{Set: "strict", Pk: "1"}, time.Minute); err == nil {
+			t.Errorf("%T: map should be rejected in strict mode", cache)
+		}
+		if err := cache.Put(&safetyNode{}, &Key{Set: "other", Pk: "1"}, time.Minute); err != nil {
+			t.Errorf("%T: pointer should be accepted in off mode: %s", cache, err)
+		}
+
+		cache.RegisterCacheSetWithOptions("no_pointers", CacheSetOptions{Limit: 10, ValueSafety: ValueSafetyNoPointers})
+		if err := cache.Put(&safetyNode{}, &Key{Set: "no_pointers", Pk: "1"}, time.Minute); err == nil {
+			t.Errorf("%T: pointer should be rejected in no_pointers mode", cache)
+		}
+		if err := cache.Put(safetyNode{}, &Key{Set: "no_pointers", Pk: "2"}, time.Minute); err != nil {
+			t.Errorf("%T: struct should be accepted in no_pointers mode: %s", cache, err)
+		}
+		if err := cache.Put(nil, &Key{Set: "no_pointers", Pk: "3"}, time.Minute); err != nil {
+			t.Errorf("%T: nil should be accepted in no_pointers mode: %s", cache, err)
+		}
+		if err := cache.RegisterCacheSetWithOptions("strict", CacheSetOptions{Limit: 10}); err != ErrSetAlreadyExists {
+			t.Errorf("%T: expected ErrSetAlreadyExists, got %v", cache, err)
+		}
+	}
+}
@@ -0,0 +1,19 @@
+package cache
+
+import "time"
+
+// GetVersioned returns value by key with version of its entry. Version is 0 if there is no entry,
+// negative entry has version, though it is not returned as value
+func (cache *StructCache) GetVersioned(key *Key) (interface{}, uint64, bool) {
+	view, result := cache.get(key)
+
+	return view.data, view.version, result == LookupHit
+}
+
+// CompareAndSwap puts element only if version of its entry is still equal to version returned by GetVersioned,
+// version 0 puts element only if there is no entry. Returns ErrVersionConflict otherwise
+func (cache *StructCache) CompareAndSwap(key *Key, version uint64, data interface{}, ttl time.Duration) error {
+	return cache.putIf(data, key, 0, ttl, func(current uint64) bool {
+		return current == version
+	})
+}
@@ -0,0 +1,88 @@
+package cache
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"go-cache/metric/dummy"
+)
+
+func TestStructCache_CompareAndSwap(t *testing.T) {
+	structCache := NewStructCacheObject(8000, nil, dummy.NewMetric())
+	k := &Key{Set: "set1", Pk: "1"}
+
+	if _, version, ok := structCache.GetVersioned(k); ok || version != 0 {
+		t.Errorf("Missing key should have version 0, got %d", version)
+	}
+
+	if err := structCache.CompareAndSwap(k, 0, "first", time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	if err := structCache.CompareAndSwap(k, 0, "again", time.Minute); err != ErrVersionConflict {
+		t.Errorf("Expected version conflict for existing key, got %v", err)
+	}
+
+	data, version, ok := structCache.GetVersioned(k)
+	if !ok || data != "first" || version == 0 {
+		t.Fatalf("Unexpected result %v, %d, %v", data, version, ok)
+	}
+
+	structCache.Put("concurrent", k, time.Minute)
+
+	if err := structCache.CompareAndSwap(k, version, "second", time.Minute); err != ErrVersionConflict {
+		t.Errorf("Expected version conflict after put, got %v", err)
+	}
+
+	_, version, _ = structCache.GetVersioned(k)
+	if err := structCache.CompareAndSwap(k, version, "second", time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := structCache.Get(k); data != "second" {
+		t.Errorf("Unexpected value %v", data)
+	}
+}
+
+func TestStructCache_CompareAndSwap_Removed(t *testing.T) {
+	structCache := NewStructCacheObject(8000, nil, dummy.NewMetric())
+	k := &Key{Set: "set1", Pk: "1"}
+
+	structCache.Put("first", k, time.Minute)
+	_, version, _ := structCache.GetVersioned(k)
+
+	structCache.Remove(k)
+	structCache.Put("second", k, time.Minute)
+
+	if err := structCache.CompareAndSwap(k, version, "third", time.Minute); err != ErrVersionConflict {
+		t.Errorf("Version should not be reused after removal, got %v", err)
+	}
+}
+
+func TestStructCache_CompareAndSwap_Concurrent(t *testing.T) {
+	structCache := NewStructCacheObject(8000, nil, dummy.NewMetric())
+	k := &Key{Set: "set1", Pk: "counter"}
+	structCache.Put(0, k, time.Minute)
+
+	const workers, increments = 8, 100
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < increments; j++ {
+				for {
+					data, version, _ := structCache.GetVersioned(k)
+					if structCache.CompareAndSwap(k, version, data.(int)+1, time.Minute) == nil {
+						break
+					}
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if data, _ := structCache.Get(k); data != workers*increments {
+		t.Errorf("Expected %d, got %v", workers*increments, data)
+	}
+}
@@ -0,0 +1,236 @@
+package cache
+
+import (
+	"bytes"
+	"context"
+	"encoding/gob"
+	"time"
+
+	"go-cache/errors"
+	"go-cache/metric"
+)
+
+const (
+	// TierL1 labels metrics of in-memory tier of TieredCache
+	TierL1 = "l1"
+	// TierL2 labels metrics of byte tier of TieredCache
+	TierL2 = "l2"
+)
+
+// Codec converts values of TieredCache to bytes stored in L2
+type Codec interface {
+	Marshal(data interface{}) ([]byte, error)
+	Unmarshal(buf []byte) (interface{}, error)
+}
+
+// GobCodec serializes values with encoding/gob.
+// Types of cached values have to be registered with gob.Register
+type GobCodec struct{}
+
+// Marshal implements Codec
+func (GobCodec) Marshal(data interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(&data); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// Unmarshal implements Codec
+func (GobCodec) Unmarshal(buf []byte) (interface{}, error) {
+	var data interface{}
+	if err := gob.NewDecoder(bytes.NewReader(buf)).Decode(&data); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// TieredOptions configures TieredCache
+type TieredOptions struct {
+	// L1TTL is TTL of values in L1, SmallCacheTTL by default. It is cut to TTL of put, values promoted from L2
+	// are kept no longer than L2 keeps them. Short L1 TTL limits staleness of values changed or removed by other processes
+	L1TTL time.Duration
+	// SetL1TTL overrides L1TTL for sets
+	SetL1TTL map[string]time.Duration
+	// Codec converts values for L2, gob by default
+	Codec Codec
+}
+
+// TieredCache composes in-memory IStructCache (L1) over IByteCache (L2). Get reads L1, then L2 promoting
+// found value to L1. Put and Remove (including tags) are propagated to both tiers
+type TieredCache struct {
+	l1      IStructCache
+	l2      IByteCache
+	options TieredOptions
+
+	logger IStructCacheLogger
+	metric metric.Metric
+}
+
+// NewTieredCache returns new instance of TieredCache
+func NewTieredCache(l1 IStructCache, l2 IByteCache, options TieredOptions, logger IStructCacheLogger, metric metric.Metric) *TieredCache {
+	if logger == nil {
+		logger = NewNilLogger()
+	}
+	if options.L1TTL <= 0 {
+		options.L1TTL = SmallCacheTTL
+	}
+	if options.Codec == nil {
+		options.Codec = GobCodec{}
+	}
+
+	return &TieredCache{
+		l1:      l1,
+		l2:      l2,
+		options: options,
+		logger:  logger,
+		metric:  metric,
+	}
+}
+
+// Get returns value by key from L1 or from L2
+func (cache *TieredCache) Get(key *Key) (interface{}, bool) {
+	data, ok, _ := cache.GetCtx(context.Background(), key)
+
+	return data, ok
+}
+
+// GetCtx returns value by key from L1 or from L2, L2 read timeout is limited by context deadline.
+// Value found in L2 is put into L1, remaining TTL of L2 value read with it limits its TTL in L1
+func (cache *TieredCache) GetCtx(ctx context.Context, key *Key) (interface{}, bool, error) {
+	ts := time.Now()
+
+	data, ok, err := cache.l1.GetCtx(ctx, key)
+	cache.updateHitOrMissCount(ok, TierL1, key)
+	cache.observe(ts, "get", TierL1, key, err)
+	if ok || err != nil {
+		return data, ok, err
+	}
+
+	ts = time.Now()
+	buf, ttl, ok, err := cache.l2.GetWithTTLCtx(ctx, key)
+	if err == nil && ok {
+		if data, err = cache.options.Codec.Unmarshal(buf); err != nil {
+			cache.logger.Warningf("tiered_cache: could not decode value of %q: %s", key, err)
+			ok = false
+		}
+	}
+	cache.updateHitOrMissCount(ok, TierL2, key)
+	cache.observe(ts, "get", TierL2, key, err)
+
+	if !ok {
+		return nil, false, err
+	}
+
+	cache.promote(data, key, ttl)
+
+	return data, true, nil
+}
+
+// promote puts value found in L2 into L1 for no longer than L2 keeps it
+func (cache *TieredCache) promote(data interface{}, key *Key, ttl time.Duration) {
+	if ttl <= 0 {
+		return
+	}
+
+	if err := cache.l1.Put(data, key, cache.l1TTL(key.Set, ttl)); err != nil {
+		cache.logger.Warningf("tiered_cache: could not promote %q to L1: %s", key, err)
+	}
+}
+
+// Put puts value into L2 and L1
+func (cache *TieredCache) Put(data interface{}, key *Key, ttl time.Duration) error {
+	return cache.PutCtx(context.Background(), data, key, ttl)
+}
+
+// PutCtx puts value into L2 and L1, L2 write timeout is limited by context deadline.
+// If L2 write fails the key is removed from L1, so L1 doesn't keep value L2 doesn't have
+func (cache *TieredCache) PutCtx(ctx context.Context, data interface{}, key *Key, ttl time.Duration) error {
+	ts := time.Now()
+
+	buf, err := cache.options.Codec.Marshal(data)
+	if err != nil {
+		return errors.Wrapf(err, "tiered_cache: could not encode value of %q", key)
+	}
+
+	err = cache.l2.PutCtx(ctx, buf, key, ttl)
+	cache.observe(ts, "put", TierL2, key, err)
+	if err != nil {
+		cache.l1.Remove(&Key{Set: key.Set, Pk: key.Pk})
+		return err
+	}
+
+	ts = time.Now()
+	err = cache.l1.Put(data, key, cache.l1TTL(key.Set, ttl))
+	cache.observe(ts, "put", TierL1, key, err)
+
+	return err
+}
+
+// Remove removes value by primary key and values having tags of the key from both tiers
+func (cache *TieredCache) Remove(key *Key) error {
+	return cache.RemoveCtx(context.Background(), key)
+}
+
+// RemoveCtx removes value by key from both tiers, L2 timeouts are limited by context deadline.
+// Values promoted from L2 are kept in L1 without tags, so remove by tags flushes the whole set in L1
+func (cache *TieredCache) RemoveCtx(ctx context.Context, key *Key) error {
+	ts := time.Now()
+	if len(key.Tags) > 0 {
+		cache.l1.FlushSet(key.Set)
+	} else {
+		cache.l1.Remove(key)
+	}
+	cache.observe(ts, "delete", TierL1, key, nil)
+
+	ts = time.Now()
+	err := cache.l2.RemoveCtx(ctx, key)
+	cache.observe(ts, "delete", TierL2, key, err)
+
+	return err
+}
+
+// l1TTL returns TTL of value in L1 cut to TTL of put, zero ttl means TTL of put is unknown
+func (cache *TieredCache) l1TTL(set string, ttl time.Duration) time.Duration {
+	l1TTL, ok := cache.options.SetL1TTL[set]
+	if !ok {
+		l1TTL = cache.options.L1TTL
+	}
+
+	if ttl > 0 && ttl < l1TTL {
+		return ttl
+	}
+
+	return l1TTL
+}
+
+func (cache *TieredCache) updateHitOrMissCount(condition bool, tier string, key *Key) {
+	labels := map[string]string{
+		metric.LabelSet:  key.Set,
+		metric.LabelTier: tier,
+	}
+
+	if condition {
+		cache.metric.RegisterHit(labels)
+		if cache.logger.IsDebugEnabled() {
+			cache.logger.Debugf("tiered_cache: %s HIT %v", tier, key)
+		}
+		return
+	}
+
+	cache.metric.RegisterMiss(labels)
+	if cache.logger.IsDebugEnabled() {
+		cache.logger.Debugf("tiered_cache: %s MISS %v", tier, key)
+	}
+}
+
+func (cache *TieredCache) observe(ts time.Time, operation, tier string, key *Key, err error) {
+	cache.metric.ObserveRT(map[string]string{
+		metric.LabelSet:       key.Set,
+		metric.LabelTier:      tier,
+		metric.LabelOperation: operation,
+		metric.LabelIsError:   metric.IsError(err),
+	}, metric.SinceMs(ts))
+}
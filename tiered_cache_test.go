@@ -0,0 +1,201 @@
+package cache
+
+import (
+	"encoding/gob"
+	"sync"
+	"testing"
+	"time"
+
+	"go-cache/metric"
+	"go-cache/metric/dummy"
+)
+
+// tierMetric counts hits and misses by tier
+type tierMetric struct {
+	dummy.Metric
+	lock   sync.Mutex
+	hits   map[string]int
+	misses map[string]int
+}
+
+func newTierMetric() *tierMetric {
+	return &tierMetric{hits: make(map[string]int), misses: make(map[string]int)}
+}
+
+func (m *tierMetric) RegisterHit(labels map[string]string) {
+	m.lock.Lock()
+	m.hits[labels[metric.LabelTier]]++
+	m.lock.Unlock()
+}
+
+func (m *tierMetric) RegisterMiss(labels map[string]string) {
+	m.lock.Lock()
+	m.misses[labels[metric.LabelTier]]++
+	m.lock.Unlock()
+}
+
+type tieredValue struct {
+	Name  string
+	Count int
+}
+
+func init() {
+	gob.Register(tieredValue{})
+}
+
+func newTestTieredCache(options TieredOptions, m metric.Metric) (*TieredCache, *StructCache, *MemoryByteCache, *FakeClock) {
+	clock := NewFakeClock(time.Now())
+
+	l1 := NewStructCacheObject(100, nil, dummy.NewMetric())
+	l1.SetClock(clock)
+	l2 := NewMemoryByteCache(100, nil, dummy.NewMetric())
+	l2.SetClock(clock)
+
+	return NewTieredCache(l1, l2, options, nil, m), l1, l2, clock
+}
+
+func TestTieredCache_Get(t *testing.T) {
+	m := newTierMetric()
+	cache, l1, l2, clock := newTestTieredCache(TieredOptions{L1TTL: time.Minute}, m)
+	k := &Key{Set: "set1", Pk: "1"}
+
+	if err := cache.Put(tieredValue{Name: "value", Count: 1}, k, time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := l2.Get(k); !ok {
+		t.Error("Value should be put into L2")
+	}
+	if ttl, _ := l1.TTL(k); ttl != time.Minute {
+		t.Errorf("L1 TTL should be %s, got %s", time.Minute, ttl)
+	}
+
+	if data, ok := cache.Get(k); !ok || data != (tieredValue{Name: "value", Count: 1}) {
+		t.Errorf("Unexpected value %v, %v", data, ok)
+	}
+
+	clock.Advance(time.Minute)
+
+	if data, ok := cache.Get(k); !ok || data != (tieredValue{Name: "value", Count: 1}) {
+		t.Errorf("Value should be read from L2, got %v, %v", data, ok)
+	}
+	if _, ok := l1.Get(k); !ok {
+		t.Error("Value found in L2 should be promoted to L1")
+	}
+
+	if _, ok := cache.Get(&Key{Set: "set1", Pk: "missing"}); ok {
+		t.Error("Missing key should not be found")
+	}
+
+	expectedHits := map[string]int{TierL1: 1, TierL2: 1}
+	expectedMisses := map[string]int{TierL1: 2, TierL2: 1}
+	for _, tier := range []string{TierL1, TierL2} {
+		if m.hits[tier] != expectedHits[tier] || m.misses[tier] != expectedMisses[tier] {
+			t.Errorf("Unexpected metrics of tier %s: %d hits, %d misses", tier, m.hits[tier], m.misses[tier])
+		}
+	}
+}
+
+func TestTieredCache_L1TTL(t *testing.T) {
+	cache, l1, _, _ := newTestTieredCache(TieredOptions{
+		L1TTL:    time.Minute,
+		SetL1TTL: map[string]time.Duration{"short": time.Second},
+	}, dummy.NewMetric())
+
+	k1 := &Key{Set: "set1", Pk: "1"}
+	cache.Put("data", k1, 10*time.Second)
+	if ttl, _ := l1.TTL(k1); ttl != 10*time.Second {
+		t.Errorf("L1 TTL should be cut to TTL of put, got %s", ttl)
+	}
+
+	k2 := &Key{Set: "short", Pk: "1"}
+	cache.Put("data", k2, time.Hour)
+	if ttl, _ := l1.TTL(k2); ttl != time.Second {
+		t.Errorf("L1 TTL of set should be used, got %s", ttl)
+	}
+}
+
+func TestTieredCache_RemoveByTag(t *testing.T) {
+	cache, l1, l2, _ := newTestTieredCache(TieredOptions{}, dummy.NewMetric())
+
+	k1 := &Key{Set: "set1", Pk: "1", Tags: []string{"tag"}}
+	k2 := &Key{Set: "set1", Pk: "2"}
+	cache.Put("data1", k1, time.Hour)
+	cache.Put("data2", k2, time.Hour)
+
+	if err := cache.Remove(&Key{Set: "set1", Tags: []string{"tag"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := l1.Get(k1); ok {
+		t.Error("Value with removed tag should be removed from L1")
+	}
+	if _, ok := l2.Get(k1); ok {
+		t.Error("Value with removed tag should be removed from L2")
+	}
+	if _, ok := cache.Get(k2); !ok {
+		t.Error("Value without removed tag should be kept")
+	}
+}
+
+func TestTieredCache_Put_L2Error(t *testing.T) {
+	l1 := NewStructCacheObject(100, nil, dummy.NewMetric())
+	l2 := NewMemoryByteCache(100, nil, dummy.NewMetric())
+	cache := NewTieredCache(l1, l2, TieredOptions{}, nil, dummy.NewMetric())
+	k := &Key{Set: "set1", Pk: "1"}
+
+	cache.Put("old", k, time.Hour)
+
+	// L2 without default TTL rejects zero TTL
+	if err := cache.Put("new", k, 0); err == nil {
+		t.Fatal("Put should fail if L2 rejects value")
+	}
+	if _, ok := l1.Get(k); ok {
+		t.Error("Key should be removed from L1 if L2 put fails")
+	}
+}
+
+func TestTieredCache_Promote_L2TTL(t *testing.T) {
+	cache, l1, l2, clock := newTestTieredCache(TieredOptions{L1TTL: time.Minute}, dummy.NewMetric())
+	k := &Key{Set: "set1", Pk: "1"}
+
+	buf, _ := GobCodec{}.Marshal("data")
+	l2.Put(buf, k, 10*time.Second)
+	clock.Advance(time.Second)
+
+	if data, ok := cache.Get(k); !ok || data != "data" {
+		t.Fatalf("Value should be read from L2, got %v, %v", data, ok)
+	}
+	if ttl, _ := l1.TTL(k); ttl != 9*time.Second {
+		t.Errorf("Promoted value should be kept in L1 no longer than in L2, got %s", ttl)
+	}
+
+	clock.Advance(9 * time.Second)
+
+	if _, ok := cache.Get(k); ok {
+		t.Error("Value expired in L2 should not be returned from L1")
+	}
+}
+
+func TestTieredCache_Remove_Tags_Promoted(t *testing.T) {
+	cache, l1, _, clock := newTestTieredCache(TieredOptions{L1TTL: time.Second}, dummy.NewMetric())
+	k := &Key{Set: "set1", Pk: "1", Tags: []string{"t"}}
+
+	if err := cache.Put("data", k, time.Minute); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	clock.Advance(2 * time.Second)
+
+	if data, ok := cache.Get(&Key{Set: "set1", Pk: "1"}); !ok || data != "data" {
+		t.Fatalf("Value should be promoted from L2, got %v, %v", data, ok)
+	}
+	if _, ok := l1.Get(k); !ok {
+		t.Fatal("Value should be kept in L1")
+	}
+
+	if err := cache.Remove(&Key{Set: "set1", Tags: []string{"t"}}); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if data, ok := cache.Get(&Key{Set: "set1", Pk: "1"}); ok {
+		t.Errorf("Promoted value should be removed by tag, got %v", data)
+	}
+}